@@ -0,0 +1,70 @@
+package opstatus
+
+import "sync"
+
+// Severity tells how urgently a failure must be brought to the attention of its owners.
+type Severity string
+
+const (
+	// SeverityPage pages the oncall of the owning team.
+	SeverityPage Severity = "page"
+	// SeverityTicket files a ticket to the owning team.
+	SeverityTicket Severity = "ticket"
+	// SeverityNone only records the failure.
+	SeverityNone Severity = "none"
+)
+
+// AlertPolicy tells how to alert on the failures of a code or a case and to whom, so that alerts
+// route to the team owning the failing case rather than to the oncall of the service by default.
+type AlertPolicy struct {
+	Severity Severity
+	// Team is the ownership label of the team to route the alerts to.
+	Team string
+	// Labels are additional labels of the alerts, e.g., a runbook or a component.
+	Labels map[string]string
+}
+
+var (
+	alertPoliciesMu     sync.RWMutex
+	codeToAlertPolicy   = map[Code]AlertPolicy{}
+	caseIDToAlertPolicy = map[string]AlertPolicy{}
+)
+
+// SetCodeAlertPolicy sets the alert policy of the statuses with given code, unless their case has a
+// policy of its own. It returns ErrFrozen once the configuration is frozen.
+func SetCodeAlertPolicy(code Code, policy AlertPolicy) error {
+	if err := checkNotFrozen(); err != nil {
+		return err
+	}
+	alertPoliciesMu.Lock()
+	defer alertPoliciesMu.Unlock()
+	codeToAlertPolicy[code] = policy
+	return nil
+}
+
+// SetCaseAlertPolicy sets the alert policy of the statuses with the case of given identifier. It
+// returns ErrFrozen once the configuration is frozen.
+func SetCaseAlertPolicy(caseID string, policy AlertPolicy) error {
+	if err := checkNotFrozen(); err != nil {
+		return err
+	}
+	alertPoliciesMu.Lock()
+	defer alertPoliciesMu.Unlock()
+	caseIDToAlertPolicy[caseID] = policy
+	return nil
+}
+
+// AlertPolicy returns the alert policy of this status: the one of its case if set, otherwise the
+// one of its code. It returns false if neither is set, in which case alerts should go to the oncall
+// of the service.
+func (s *Status) AlertPolicy() (AlertPolicy, bool) {
+	alertPoliciesMu.RLock()
+	defer alertPoliciesMu.RUnlock()
+	if s.theCase != nil {
+		if policy, found := caseIDToAlertPolicy[s.theCase.Identifier()]; found {
+			return policy, true
+		}
+	}
+	policy, found := codeToAlertPolicy[s.code]
+	return policy, found
+}
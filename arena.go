@@ -0,0 +1,72 @@
+package opstatus
+
+const defaultArenaChunkSize = 64
+
+// StatusArena amortizes allocations when a large number of short-lived statuses is built for a
+// single request, e.g., per-item statuses of a batch validation. Statuses obtained from an arena
+// are only valid until Release is called. Use Keep to turn the ones that must survive the request
+// into normal statuses.
+//
+// A StatusArena is not safe for concurrent use.
+type StatusArena struct {
+	chunks    [][]Status
+	chunkSize int
+	chunk     int // index of the chunk being filled
+	next      int // index of the next free slot in the chunk being filled
+}
+
+// NewStatusArena returns an arena that allocates statuses in chunks of the given size. If the size
+// is not positive, a default chunk size is used.
+func NewStatusArena(chunkSize int) *StatusArena {
+	if chunkSize <= 0 {
+		chunkSize = defaultArenaChunkSize
+	}
+	return &StatusArena{chunkSize: chunkSize}
+}
+
+// New returns a status with given code allocated from this arena.
+func (a *StatusArena) New(code Code) *Status {
+	if a.chunk == len(a.chunks) {
+		a.chunks = append(a.chunks, make([]Status, a.chunkSize))
+	}
+	s := &a.chunks[a.chunk][a.next]
+	*s = newStatus(code)
+	s.details = map[string]any{}
+	a.next++
+	if a.next == a.chunkSize {
+		a.chunk++
+		a.next = 0
+	}
+	return s
+}
+
+// Len returns the number of statuses currently allocated from this arena.
+func (a *StatusArena) Len() int {
+	return a.chunk*a.chunkSize + a.next
+}
+
+// Keep returns a copy of given arena-allocated status that does not share any memory with the
+// arena, so that it stays valid after the arena is released.
+func (a *StatusArena) Keep(s *Status) *Status {
+	kept := *s
+	if s.details != nil {
+		kept.details = copyDetails(s.details)
+	}
+	return &kept
+}
+
+// Release invalidates all the statuses allocated from this arena and makes their memory available
+// for reuse.
+func (a *StatusArena) Release() {
+	for i := 0; i <= a.chunk && i < len(a.chunks); i++ {
+		used := a.chunks[i]
+		if i == a.chunk {
+			used = used[:a.next]
+		}
+		for j := range used {
+			used[j] = Status{}
+		}
+	}
+	a.chunk = 0
+	a.next = 0
+}
@@ -0,0 +1,27 @@
+package opstatus
+
+import "testing"
+
+func TestArenaStatusesTakeDetails(t *testing.T) {
+	arena := NewStatusArena(2)
+	s := arena.New(CodeInvalidArgument)
+	s.AddDetail("item", 3)
+
+	kept := arena.Keep(s)
+	arena.Release()
+	if value, found := kept.Detail("item"); !found || value != 3 {
+		t.Errorf("kept.Detail(item) = %v, %v, want 3", value, found)
+	}
+}
+
+func TestAddDetailOnAPrototypePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("AddDetail on StatusInternal didn't panic")
+		}
+		if details := StatusInternal.WithDescription("x").Details(); len(details) != 0 {
+			t.Errorf("StatusInternal details = %v, want none", details)
+		}
+	}()
+	StatusInternal.AddDetail("k", "v")
+}
@@ -0,0 +1,117 @@
+package opstatus
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// CaseID is the plain Case a status is decoded with when the case carries no domain or its domain
+// has no registered codec.
+type CaseID string
+
+func (c CaseID) Identifier() string {
+	return string(c)
+}
+
+// DomainCase is a Case richer than its identifier, e.g., with parameters, whose domain names the
+// CaseCodec that serializes it.
+type DomainCase interface {
+	Case
+	Domain() string
+}
+
+// CaseCodec serializes the parameters of the cases of a domain, so that a receiving service can
+// reconstruct typed cases rather than bare identifiers.
+type CaseCodec interface {
+	EncodeParams(c DomainCase) (json.RawMessage, error)
+	DecodeCase(identifier string, params json.RawMessage) (Case, error)
+}
+
+// encodedCase is the serialized form of a DomainCase. Other cases are serialized as their
+// identifier string.
+type encodedCase struct {
+	Domain     string          `json:"domain"`
+	Identifier string          `json:"identifier"`
+	Params     json.RawMessage `json:"params,omitempty"`
+}
+
+var (
+	caseCodecsMu sync.RWMutex
+	caseCodecs   = map[string]CaseCodec{}
+)
+
+// RegisterCaseCodec registers the codec of the cases of given domain. It returns ErrFrozen once the
+// configuration is frozen.
+func RegisterCaseCodec(domain string, codec CaseCodec) error {
+	if err := checkNotFrozen(); err != nil {
+		return err
+	}
+	if domain == "" || codec == nil {
+		return fmt.Errorf("case codec requires a domain and a codec")
+	}
+	caseCodecsMu.Lock()
+	defer caseCodecsMu.Unlock()
+	caseCodecs[domain] = codec
+	return nil
+}
+
+func lookupCaseCodec(domain string) CaseCodec {
+	caseCodecsMu.RLock()
+	defer caseCodecsMu.RUnlock()
+	return caseCodecs[domain]
+}
+
+// EncodeCase serializes given case to JSON, which is also fit for a header value: a DomainCase
+// whose domain has a registered codec as an object holding its domain, identifier and parameters,
+// any other case as its identifier string.
+func EncodeCase(c Case) ([]byte, error) {
+	if c == nil {
+		return []byte("null"), nil
+	}
+	domainCase, ok := c.(DomainCase)
+	if !ok {
+		return json.Marshal(c.Identifier())
+	}
+	codec := lookupCaseCodec(domainCase.Domain())
+	if codec == nil {
+		return json.Marshal(c.Identifier())
+	}
+	params, err := codec.EncodeParams(domainCase)
+	if err != nil {
+		return nil, fmt.Errorf("encode case %s: %w", c.Identifier(), err)
+	}
+	return json.Marshal(encodedCase{
+		Domain:     domainCase.Domain(),
+		Identifier: c.Identifier(),
+		Params:     params,
+	})
+}
+
+// DecodeCase reconstructs a case serialized by EncodeCase. A case whose domain has no codec
+// registered on this side decodes to its CaseID, so that peers need not share all their codecs.
+func DecodeCase(data []byte) (Case, error) {
+	var identifier string
+	if err := json.Unmarshal(data, &identifier); err == nil {
+		if identifier == "" {
+			return nil, nil
+		}
+		return CaseID(identifier), nil
+	}
+	var encoded encodedCase
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, fmt.Errorf("decode case: %w", err)
+	}
+	if encoded.Identifier == "" {
+		return nil, fmt.Errorf("decode case: missing identifier")
+	}
+	codec := lookupCaseCodec(encoded.Domain)
+	if codec == nil {
+		return CaseID(encoded.Identifier), nil
+	}
+	c, err := codec.DecodeCase(encoded.Identifier, encoded.Params)
+	if err != nil {
+		return nil, fmt.Errorf("decode case %s: %w", encoded.Identifier, err)
+	}
+	return c, nil
+}
@@ -0,0 +1,38 @@
+package opstatus
+
+import "fmt"
+
+// CaseOwner tells who owns a registered case, so that its failures can be routed to and
+// documented by the owning team.
+type CaseOwner struct {
+	Team       string `json:"team,omitempty"`
+	Contact    string `json:"contact,omitempty"`
+	RunbookURL string `json:"runbook_url,omitempty"`
+}
+
+// Owner returns the owner of this case, which is zero if none was set.
+func (c RegisteredCase) Owner() CaseOwner {
+	return c.owner
+}
+
+// caseOwners are the owners set by SetCaseOwner, which prevail over the ones of the registry
+// documents, reloaded ones included. It is guarded by caseRegistryMu.
+var caseOwners = map[string]CaseOwner{}
+
+// SetCaseOwner sets the owner of the registered case with given identifier. It returns ErrFrozen
+// once the configuration is frozen.
+func SetCaseOwner(identifier string, owner CaseOwner) error {
+	if err := checkNotFrozen(); err != nil {
+		return err
+	}
+	caseRegistryMu.Lock()
+	defer caseRegistryMu.Unlock()
+	registered, found := caseRegistry[identifier]
+	if !found {
+		return fmt.Errorf("case %s is not registered", identifier)
+	}
+	registered.owner = owner
+	caseRegistry[identifier] = registered
+	caseOwners[identifier] = owner
+	return nil
+}
@@ -0,0 +1,33 @@
+// Package cases provides the Case type of the optional case catalogs in its subpackages, which give
+// teams a shared starting vocabulary of business error cases.
+package cases
+
+import "github.com/ikonglong/op-status"
+
+// Case is a business error case with the code its statuses default to.
+type Case struct {
+	identifier  string
+	defaultCode opstatus.Code
+}
+
+// New returns a case with given identifier and default code.
+func New(identifier string, defaultCode opstatus.Code) Case {
+	return Case{
+		identifier:  identifier,
+		defaultCode: defaultCode,
+	}
+}
+
+func (c Case) Identifier() string {
+	return c.identifier
+}
+
+// DefaultCode returns the code statuses of this case default to.
+func (c Case) DefaultCode() opstatus.Code {
+	return c.defaultCode
+}
+
+// Status returns a status of this case with its default code and given description.
+func (c Case) Status(description string) *opstatus.Status {
+	return opstatus.NewWithCode(c.defaultCode).WithCaseAndDesc(c, description)
+}
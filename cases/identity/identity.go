@@ -0,0 +1,18 @@
+// Package identity is a catalog of common identity and access cases.
+package identity
+
+import (
+	"github.com/ikonglong/op-status"
+	"github.com/ikonglong/op-status/cases"
+)
+
+var (
+	CredentialsMissing = cases.New("identity.credentials_missing", opstatus.CodeUnauthenticated)
+	CredentialsInvalid = cases.New("identity.credentials_invalid", opstatus.CodeUnauthenticated)
+	TokenExpired       = cases.New("identity.token_expired", opstatus.CodeUnauthenticated)
+	AccountLocked      = cases.New("identity.account_locked", opstatus.CodePermissionDenied)
+	AccountDisabled    = cases.New("identity.account_disabled", opstatus.CodePermissionDenied)
+	PermissionMissing  = cases.New("identity.permission_missing", opstatus.CodePermissionDenied)
+	UserNotFound       = cases.New("identity.user_not_found", opstatus.CodeNotFound)
+	UserAlreadyExists  = cases.New("identity.user_already_exists", opstatus.CodeAlreadyExists)
+)
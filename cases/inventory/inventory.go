@@ -0,0 +1,16 @@
+// Package inventory is a catalog of common inventory cases.
+package inventory
+
+import (
+	"github.com/ikonglong/op-status"
+	"github.com/ikonglong/op-status/cases"
+)
+
+var (
+	InsufficientInventory = cases.New("inventory.insufficient_inventory", opstatus.CodeFailedPrecondition)
+	SKUNotFound           = cases.New("inventory.sku_not_found", opstatus.CodeNotFound)
+	PurchaseLimitExceeded = cases.New("inventory.purchase_limit_exceeded", opstatus.CodeFailedPrecondition)
+	ReservationExpired    = cases.New("inventory.reservation_expired", opstatus.CodeFailedPrecondition)
+	StockVersionConflict  = cases.New("inventory.stock_version_conflict", opstatus.CodeAborted)
+	InvalidQuantity       = cases.New("inventory.invalid_quantity", opstatus.CodeInvalidArgument)
+)
@@ -0,0 +1,19 @@
+// Package payments is a catalog of common payment cases.
+package payments
+
+import (
+	"github.com/ikonglong/op-status"
+	"github.com/ikonglong/op-status/cases"
+)
+
+var (
+	CardDeclined          = cases.New("payments.card_declined", opstatus.CodeFailedPrecondition)
+	CardExpired           = cases.New("payments.card_expired", opstatus.CodeFailedPrecondition)
+	InsufficientFunds     = cases.New("payments.insufficient_funds", opstatus.CodeFailedPrecondition)
+	InvalidCardNumber     = cases.New("payments.invalid_card_number", opstatus.CodeInvalidArgument)
+	CurrencyNotSupported  = cases.New("payments.currency_not_supported", opstatus.CodeInvalidArgument)
+	DuplicatePayment      = cases.New("payments.duplicate_payment", opstatus.CodeAlreadyExists)
+	PaymentNotFound       = cases.New("payments.payment_not_found", opstatus.CodeNotFound)
+	RefundExceedsCaptured = cases.New("payments.refund_exceeds_captured", opstatus.CodeFailedPrecondition)
+	ProcessorUnavailable  = cases.New("payments.processor_unavailable", opstatus.CodeUnavailable)
+)
@@ -0,0 +1,14 @@
+// Package quota is a catalog of common quota and rate limiting cases.
+package quota
+
+import (
+	"github.com/ikonglong/op-status"
+	"github.com/ikonglong/op-status/cases"
+)
+
+var (
+	RateLimitExceeded   = cases.New("quota.rate_limit_exceeded", opstatus.CodeResourceExhausted)
+	QuotaExceeded       = cases.New("quota.quota_exceeded", opstatus.CodeResourceExhausted)
+	ConcurrencyExceeded = cases.New("quota.concurrency_exceeded", opstatus.CodeResourceExhausted)
+	StorageExhausted    = cases.New("quota.storage_exhausted", opstatus.CodeResourceExhausted)
+)
@@ -0,0 +1,88 @@
+package opstatus
+
+import (
+	"crypto/rand"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// Clock is the source of time of the package: timestamps, elapsed times and retry delays. Tests of
+// consuming services can replace it with SetClock to be deterministic.
+type Clock interface {
+	Now() time.Time
+	// After waits for given duration and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+type clockHolder struct{ Clock }
+
+type randomHolder struct{ io.Reader }
+
+var (
+	clock  atomic.Pointer[clockHolder]
+	random atomic.Pointer[randomHolder]
+)
+
+func init() {
+	clock.Store(&clockHolder{systemClock{}})
+	random.Store(&randomHolder{rand.Reader})
+}
+
+// SetClock replaces the clock of the package; nil restores the system clock. It returns ErrFrozen
+// once the configuration is frozen.
+func SetClock(c Clock) error {
+	if err := checkNotFrozen(); err != nil {
+		return err
+	}
+	if c == nil {
+		c = systemClock{}
+	}
+	clock.Store(&clockHolder{c})
+	return nil
+}
+
+// SetRandom replaces the source of randomness of the package, e.g., of operation IDs; nil restores
+// crypto/rand. It returns ErrFrozen once the configuration is frozen.
+func SetRandom(r io.Reader) error {
+	if err := checkNotFrozen(); err != nil {
+		return err
+	}
+	if r == nil {
+		r = rand.Reader
+	}
+	random.Store(&randomHolder{r})
+	return nil
+}
+
+// Now returns the current time of the package clock, for the packages building on this one to
+// honor SetClock.
+func Now() time.Time {
+	return now()
+}
+
+func now() time.Time {
+	return clock.Load().Now()
+}
+
+func since(t time.Time) time.Duration {
+	return now().Sub(t)
+}
+
+func after(d time.Duration) <-chan time.Time {
+	return clock.Load().After(d)
+}
+
+func randomReader() io.Reader {
+	return random.Load().Reader
+}
@@ -0,0 +1,174 @@
+// Command opstatus-backfill classifies historic log lines into the op-status taxonomy, so that teams
+// can quantify their error landscape before migrating their code. Each line matching a rule is
+// emitted as a JSON record holding its code, case and short reference fingerprint.
+//
+// Usage:
+//
+//	opstatus-backfill -rules rules.json [-unmatched] [-summary] [log ...]
+//
+// Logs are read from stdin if no file is given. The rules file is a JSON array of rules tried in
+// order, the first matching one wins:
+//
+//	[{"name": "db-timeout", "pattern": "timeout talking to (\\w+)", "code": "DeadlineExceeded", "case": "${1}_timeout"}]
+//
+// The case may reference the capture groups of the pattern as regexp.Expand does.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+
+	"github.com/ikonglong/op-status"
+)
+
+type rule struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+	Code    string `json:"code"`
+	Case    string `json:"case,omitempty"`
+
+	regexp *regexp.Regexp
+	code   opstatus.Code
+}
+
+type record struct {
+	Source      string `json:"source"`
+	Line        int    `json:"line"`
+	Rule        string `json:"rule,omitempty"`
+	Code        string `json:"code"`
+	Case        string `json:"case,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+func main() {
+	rulesPath := flag.String("rules", "", "JSON file of classification rules")
+	unmatched := flag.Bool("unmatched", false, "emit the lines no rule matches as Unknown")
+	summary := flag.Bool("summary", false, "print the number of records per fingerprint to stderr")
+	flag.Parse()
+
+	rules, err := loadRules(*rulesPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	out := json.NewEncoder(os.Stdout)
+	counts := map[string]int{}
+	emit := func(r record) error {
+		counts[r.Fingerprint]++
+		return out.Encode(r)
+	}
+
+	sources := flag.Args()
+	if len(sources) == 0 {
+		if err := classify("stdin", os.Stdin, rules, *unmatched, emit); err != nil {
+			log.Fatal(err)
+		}
+	}
+	for _, source := range sources {
+		f, err := os.Open(source)
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = classify(source, f, rules, *unmatched, emit)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *summary {
+		printSummary(os.Stderr, counts)
+	}
+}
+
+func loadRules(path string) ([]*rule, error) {
+	if path == "" {
+		return nil, fmt.Errorf("missing -rules")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []*rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parse rules %s: %w", path, err)
+	}
+	for i, r := range rules {
+		if r.regexp, err = regexp.Compile(r.Pattern); err != nil {
+			return nil, fmt.Errorf("rule %d (%s): %w", i, r.Name, err)
+		}
+		var found bool
+		if r.code, found = opstatus.CodeByName(r.Code); !found {
+			return nil, fmt.Errorf("rule %d (%s): unknown code %q", i, r.Name, r.Code)
+		}
+	}
+	return rules, nil
+}
+
+func classify(source string, in io.Reader, rules []*rule, unmatched bool, emit func(record) error) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Bytes()
+		r, theCase := match(rules, line)
+		if r == nil && !unmatched {
+			continue
+		}
+		status := opstatus.NewWithCode(opstatus.CodeUnknown)
+		rec := record{Source: source, Line: n}
+		if r != nil {
+			status = opstatus.NewWithCode(r.code)
+			rec.Rule = r.Name
+		}
+		if theCase != "" {
+			status = status.WithCase(opstatus.CaseID(theCase))
+			rec.Case = theCase
+		}
+		rec.Code = status.Code().Name()
+		rec.Fingerprint = status.ShortRef().String()
+		if err := emit(rec); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read %s: %w", source, err)
+	}
+	return nil
+}
+
+// match returns the first rule matching given line and the case it expands to.
+func match(rules []*rule, line []byte) (*rule, string) {
+	for _, r := range rules {
+		submatches := r.regexp.FindSubmatchIndex(line)
+		if submatches == nil {
+			continue
+		}
+		if r.Case == "" {
+			return r, ""
+		}
+		return r, string(r.regexp.Expand(nil, []byte(r.Case), line, submatches))
+	}
+	return nil, ""
+}
+
+func printSummary(w io.Writer, counts map[string]int) {
+	fingerprints := make([]string, 0, len(counts))
+	for fingerprint := range counts {
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	sort.Slice(fingerprints, func(i, j int) bool {
+		if counts[fingerprints[i]] != counts[fingerprints[j]] {
+			return counts[fingerprints[i]] > counts[fingerprints[j]]
+		}
+		return fingerprints[i] < fingerprints[j]
+	})
+	for _, fingerprint := range fingerprints {
+		fmt.Fprintf(w, "%8d %s\n", counts[fingerprint], fingerprint)
+	}
+}
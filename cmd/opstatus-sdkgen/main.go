@@ -0,0 +1,264 @@
+// Command opstatus-sdkgen emits the operation status codes, with their values, HTTP mappings and
+// retry advice, and the cases of the registry, with their codes and default descriptions, as
+// constants of client SDK languages, so that polyglot SDKs stay in sync with the Go source of truth.
+//
+// Usage:
+//
+//	opstatus-sdkgen -lang typescript|java|python [-registry file]... [-out file]
+//
+// The registry files are registry documents loaded with opstatus.LoadRegistry, whose HTTP overrides
+// apply to the emitted mappings too.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/ikonglong/op-status"
+)
+
+type codeData struct {
+	Name        string
+	ConstName   string
+	Value       int
+	HTTPStatus  int
+	RetryAdvice string
+}
+
+type caseData struct {
+	Identifier         string
+	ConstName          string
+	Code               codeData
+	DefaultDescription string
+	DocsURL            string
+}
+
+type sdkData struct {
+	Codes []codeData
+	Cases []caseData
+}
+
+var templates = map[string]string{
+	"typescript": `// Code generated by opstatus-sdkgen. DO NOT EDIT.
+
+export interface OpStatusCode {
+  readonly name: string;
+  readonly value: number;
+  readonly httpStatus: number;
+  readonly retryAdvice: string;
+}
+
+export const OpStatusCodes = {
+{{- range .Codes}}
+  {{.Name}}: { name: "{{.Name}}", value: {{.Value}}, httpStatus: {{.HTTPStatus}}, retryAdvice: "{{.RetryAdvice}}" },
+{{- end}}
+} as const;
+
+export interface OpStatusCase {
+  readonly identifier: string;
+  readonly code: OpStatusCode;
+  readonly defaultDescription: string;
+  readonly docsUrl: string;
+}
+
+export const OpStatusCases = {
+{{- range .Cases}}
+  {{.ConstName}}: { identifier: {{quote .Identifier}}, code: OpStatusCodes.{{.Code.Name}}, defaultDescription: {{quote .DefaultDescription}}, docsUrl: {{quote .DocsURL}} },
+{{- end}}
+} as const;
+`,
+	"java": `// Code generated by opstatus-sdkgen. DO NOT EDIT.
+
+public enum OpStatusCode {
+{{- range $i, $c := .Codes}}{{if $i}},{{end}}
+  {{$c.ConstName}}("{{$c.Name}}", {{$c.Value}}, {{$c.HTTPStatus}}, "{{$c.RetryAdvice}}")
+{{- end}};
+
+  private final String codeName;
+  private final int value;
+  private final int httpStatus;
+  private final String retryAdvice;
+
+  OpStatusCode(String codeName, int value, int httpStatus, String retryAdvice) {
+    this.codeName = codeName;
+    this.value = value;
+    this.httpStatus = httpStatus;
+    this.retryAdvice = retryAdvice;
+  }
+
+  public String codeName() { return codeName; }
+  public int value() { return value; }
+  public int httpStatus() { return httpStatus; }
+  public String retryAdvice() { return retryAdvice; }
+
+  public enum Case {
+{{- range $i, $c := .Cases}}{{if $i}},{{end}}
+    {{$c.ConstName}}({{quote $c.Identifier}}, OpStatusCode.{{$c.Code.ConstName}}, {{quote $c.DefaultDescription}}, {{quote $c.DocsURL}})
+{{- end}};
+
+    private final String identifier;
+    private final OpStatusCode code;
+    private final String defaultDescription;
+    private final String docsUrl;
+
+    Case(String identifier, OpStatusCode code, String defaultDescription, String docsUrl) {
+      this.identifier = identifier;
+      this.code = code;
+      this.defaultDescription = defaultDescription;
+      this.docsUrl = docsUrl;
+    }
+
+    public String identifier() { return identifier; }
+    public OpStatusCode code() { return code; }
+    public String defaultDescription() { return defaultDescription; }
+    public String docsUrl() { return docsUrl; }
+  }
+}
+`,
+	"python": `# Code generated by opstatus-sdkgen. DO NOT EDIT.
+
+import enum
+
+
+class OpStatusCode(enum.Enum):
+{{- range .Codes}}
+    {{.ConstName}} = ("{{.Name}}", {{.Value}}, {{.HTTPStatus}}, "{{.RetryAdvice}}")
+{{- end}}
+
+    def __init__(self, code_name, value, http_status, retry_advice):
+        self.code_name = code_name
+        self.code_value = value
+        self.http_status = http_status
+        self.retry_advice = retry_advice
+
+
+class OpStatusCase(enum.Enum):
+{{- range .Cases}}
+    {{.ConstName}} = ({{quote .Identifier}}, OpStatusCode.{{.Code.ConstName}}, {{quote .DefaultDescription}}, {{quote .DocsURL}})
+{{- else}}
+    pass
+{{- end}}
+
+    def __init__(self, identifier, code, default_description, docs_url):
+        self.identifier = identifier
+        self.code = code
+        self.default_description = default_description
+        self.docs_url = docs_url
+`,
+}
+
+// registryFlags are the paths of the registry documents to load.
+type registryFlags []string
+
+func (f *registryFlags) String() string { return strings.Join(*f, ",") }
+
+func (f *registryFlags) Set(path string) error {
+	*f = append(*f, path)
+	return nil
+}
+
+func main() {
+	lang := flag.String("lang", "", "target language: typescript, java or python")
+	out := flag.String("out", "", "output file (default stdout)")
+	var registries registryFlags
+	flag.Var(&registries, "registry", "registry document whose cases to emit (repeatable)")
+	flag.Parse()
+
+	text, found := templates[*lang]
+	if !found {
+		log.Fatalf("unsupported language %q", *lang)
+	}
+	for _, path := range registries {
+		if problems := opstatus.LoadRegistry(os.DirFS(filepath.Dir(path)), filepath.Base(path)); !problems.IsOK() {
+			log.Fatalf("load registry %s: %v", path, problems.Statuses())
+		}
+	}
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := generate(w, text); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generate(w io.Writer, text string) error {
+	tmpl, err := template.New("sdk").Funcs(template.FuncMap{"quote": quote}).Parse(text)
+	if err != nil {
+		return fmt.Errorf("parse template: %w", err)
+	}
+	codes := opstatus.Codes()
+	data := sdkData{Codes: make([]codeData, 0, len(codes))}
+	for _, code := range codes {
+		data.Codes = append(data.Codes, newCodeData(code))
+	}
+	for _, registered := range opstatus.RegisteredCases() {
+		data.Cases = append(data.Cases, caseData{
+			Identifier:         registered.Identifier(),
+			ConstName:          caseConstName(registered.Identifier()),
+			Code:               newCodeData(registered.Code()),
+			DefaultDescription: registered.DefaultDescription(),
+			DocsURL:            registered.DocsURL(),
+		})
+	}
+	return tmpl.Execute(w, data)
+}
+
+func newCodeData(code opstatus.Code) codeData {
+	return codeData{
+		Name:        code.Name(),
+		ConstName:   upperSnakeCase(code.Name()),
+		Value:       code.Value(),
+		HTTPStatus:  code.HTTPStatus(),
+		RetryAdvice: string(opstatus.NewWithCode(code).RetryAdvice()),
+	}
+}
+
+// quote returns given string as a JSON string literal, which is a valid string literal in all the
+// target languages.
+func quote(s string) (string, error) {
+	quoted, err := json.Marshal(s)
+	return string(quoted), err
+}
+
+// upperSnakeCase converts a CamelCase name to UPPER_SNAKE_CASE, e.g., NotFound to NOT_FOUND.
+func upperSnakeCase(name string) string {
+	var b strings.Builder
+	prev := rune(0)
+	for _, r := range name {
+		if unicode.IsUpper(r) && unicode.IsLower(prev) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+		prev = r
+	}
+	return b.String()
+}
+
+// caseConstName converts a case identifier to an identifier of all the target languages, e.g.,
+// payments.card_declined to PAYMENTS_CARD_DECLINED.
+func caseConstName(identifier string) string {
+	name := strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return '_'
+		}
+		return r
+	}, upperSnakeCase(identifier))
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "_" + name
+	}
+	return name
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ikonglong/op-status"
+)
+
+func TestGenerateEmitsTheRegisteredCases(t *testing.T) {
+	fsys := fstest.MapFS{"registry.json": {Data: []byte(`{"cases": [{
+		"identifier": "orders.order_not_found", "code": "NotFound",
+		"description": "order \"gone\"", "docs_url": "https://docs/errors/order_not_found"
+	}]}`)}}
+	if problems := opstatus.LoadRegistry(fsys, "registry.json"); !problems.IsOK() {
+		t.Fatal(problems.Statuses())
+	}
+	want := map[string]string{
+		"typescript": `ORDERS_ORDER_NOT_FOUND: { identifier: "orders.order_not_found", code: OpStatusCodes.NotFound, defaultDescription: "order \"gone\"", docsUrl: "https://docs/errors/order_not_found" }`,
+		"java":       `ORDERS_ORDER_NOT_FOUND("orders.order_not_found", OpStatusCode.NOT_FOUND, "order \"gone\"", "https://docs/errors/order_not_found")`,
+		"python":     `ORDERS_ORDER_NOT_FOUND = ("orders.order_not_found", OpStatusCode.NOT_FOUND, "order \"gone\"", "https://docs/errors/order_not_found")`,
+	}
+	for lang, text := range templates {
+		var b strings.Builder
+		if err := generate(&b, text); err != nil {
+			t.Fatalf("%s: %v", lang, err)
+		}
+		if !strings.Contains(b.String(), want[lang]) {
+			t.Errorf("%s output doesn't declare the case as %s:\n%s", lang, want[lang], b.String())
+		}
+	}
+}
+
+func TestCaseConstName(t *testing.T) {
+	for identifier, want := range map[string]string{
+		"payments.card_declined": "PAYMENTS_CARD_DECLINED",
+		"OrderNotFound":          "ORDER_NOT_FOUND",
+		"3ds-required":           "_3DS_REQUIRED",
+	} {
+		if got := caseConstName(identifier); got != want {
+			t.Errorf("caseConstName(%q) = %q, want %q", identifier, got, want)
+		}
+	}
+}
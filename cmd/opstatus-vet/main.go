@@ -0,0 +1,21 @@
+// Command opstatus-vet reports misuses of the op-status taxonomy in Go packages, as documented by
+// the opstatusvet package.
+//
+// Usage:
+//
+//	opstatus-vet [-fix] [package ...]
+//
+// It can also be run by go vet, along with the standard checks:
+//
+//	go vet -vettool=$(which opstatus-vet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/ikonglong/op-status/opstatusvet"
+)
+
+func main() {
+	singlechecker.Main(opstatusvet.Analyzer)
+}
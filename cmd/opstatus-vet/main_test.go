@@ -0,0 +1,24 @@
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestGoVetRunsTheAnalyzer builds the command and runs it as the vet tool of go vet over this
+// repository, which must pass.
+func TestGoVetRunsTheAnalyzer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs go vet")
+	}
+	tool := filepath.Join(t.TempDir(), "opstatus-vet")
+	if out, err := exec.Command("go", "build", "-o", tool, ".").CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	vet := exec.Command("go", "vet", "-vettool="+tool, "./...")
+	vet.Dir = filepath.Join("..", "..")
+	if out, err := vet.CombinedOutput(); err != nil {
+		t.Fatalf("go vet: %v\n%s", err, out)
+	}
+}
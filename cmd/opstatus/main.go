@@ -0,0 +1,103 @@
+// Command opstatus reads the status artifact of a pipeline step, so that shell pipelines can print
+// structured failure information and branch on it.
+//
+// Usage:
+//
+//	opstatus show [-artifact file]
+//	opstatus is ok|transient|permanent|<code name> [-artifact file]
+//
+// The artifact defaults to the file named by the OPSTATUS_ARTIFACT environment variable. show prints
+// the status as indented JSON. is exits with 0 if the status matches, with 1 if it doesn't and with 2
+// on errors, e.g.:
+//
+//	if opstatus is transient; then retry_step; fi
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ikonglong/op-status"
+	"github.com/ikonglong/op-status/process"
+)
+
+const usage = "usage: opstatus show|is [ok|transient|permanent|<code name>] [-artifact file]"
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command with given arguments and returns its exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+	command, args := args[0], args[1:]
+	var condition string
+	if command == "is" {
+		if len(args) == 0 {
+			fmt.Fprintln(stderr, usage)
+			return 2
+		}
+		condition, args = args[0], args[1:]
+	}
+	flags := flag.NewFlagSet("opstatus "+command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	artifact := flags.String("artifact", os.Getenv(process.EnvArtifact), "status artifact to read")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *artifact == "" {
+		fmt.Fprintf(stderr, "opstatus: no artifact given and %s is not set\n", process.EnvArtifact)
+		return 2
+	}
+	status, err := process.ReadArtifact(*artifact)
+	if err != nil {
+		fmt.Fprintf(stderr, "opstatus: %v\n", err)
+		return 2
+	}
+
+	switch command {
+	case "show":
+		encoded, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			fmt.Fprintf(stderr, "opstatus: %v\n", err)
+			return 2
+		}
+		fmt.Fprintln(stdout, string(encoded))
+		return 0
+	case "is":
+		matches, err := is(status, condition)
+		if err != nil {
+			fmt.Fprintf(stderr, "opstatus: %v\n", err)
+			return 2
+		}
+		if !matches {
+			return 1
+		}
+		return 0
+	}
+	fmt.Fprintln(stderr, usage)
+	return 2
+}
+
+// is tells if given status meets given condition: ok, transient, permanent or a code name.
+func is(status *opstatus.Status, condition string) (bool, error) {
+	switch condition {
+	case "ok":
+		return status.IsOK(), nil
+	case "transient":
+		return !status.IsOK() && status.IsTransient(), nil
+	case "permanent":
+		return !status.IsOK() && !status.IsTransient(), nil
+	}
+	code, found := opstatus.CodeByName(condition)
+	if !found {
+		return false, fmt.Errorf("unknown code %q", condition)
+	}
+	return status.Code() == code, nil
+}
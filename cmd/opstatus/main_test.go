@@ -0,0 +1,41 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ikonglong/op-status"
+	"github.com/ikonglong/op-status/process"
+)
+
+func TestBranchOnTheArtifact(t *testing.T) {
+	artifact := filepath.Join(t.TempDir(), "status.json")
+	if err := process.WriteArtifact(artifact, opstatus.StatusUnavailable.WithDescription("registry is down")); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(process.EnvArtifact, artifact)
+
+	for condition, want := range map[string]int{
+		"transient":          0,
+		"permanent":          1,
+		"ok":                 1,
+		"ServiceUnavailable": 0,
+		"NotFound":           1,
+		"Bogus":              2,
+	} {
+		var stdout, stderr strings.Builder
+		if got := run([]string{"is", condition}, &stdout, &stderr); got != want {
+			t.Errorf("opstatus is %s = %d, want %d (%s)", condition, got, want, stderr.String())
+		}
+	}
+
+	var stdout, stderr strings.Builder
+	if got := run([]string{"show", "-artifact", artifact}, &stdout, &stderr); got != 0 || !strings.Contains(stdout.String(), "registry is down") {
+		t.Errorf("opstatus show = %d %q %q", got, stdout.String(), stderr.String())
+	}
+	t.Setenv(process.EnvArtifact, "")
+	if got := run([]string{"show"}, &stdout, &stderr); got != 2 {
+		t.Errorf("opstatus show without artifact = %d, want 2", got)
+	}
+}
@@ -204,6 +204,26 @@ var codeList = func() []Code {
 	return list
 }()
 
+// Codes returns all the well-defined codes ordered by their values.
+func Codes() []Code {
+	return append([]Code(nil), codeList...)
+}
+
+// CodeByName returns the well-defined code with given name, e.g., "NotFound".
+func CodeByName(name string) (Code, bool) {
+	for _, code := range codeList {
+		if code.name == name {
+			return code, true
+		}
+	}
+	return Code{}, false
+}
+
+// Name returns the name of this code.
+func (c Code) Name() string {
+	return c.name
+}
+
 // Value returns the numerical value of this code.
 func (c Code) Value() int {
 	return c.value
@@ -216,7 +236,12 @@ func (c Code) toStatus() Status {
 
 // toHTTPStatus returns the HTTPStatus corresponding to this status code.
 func (c Code) toHTTPStatus() http.Status {
-	return codeToHTTPStatus[c]
+	return currentHTTPMapping()[c]
+}
+
+// HTTPStatus returns the HTTP status code this code is mapped to.
+func (c Code) HTTPStatus() int {
+	return int(c.toHTTPStatus())
 }
 
 func (c Code) String() string {
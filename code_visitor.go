@@ -0,0 +1,65 @@
+package opstatus
+
+// CodeVisitor has one method per canonical code. Handling codes through VisitCode instead of a
+// switch statement turns the addition of a new code into a compile error for every implementation,
+// rather than letting it silently fall into a default branch.
+type CodeVisitor[T any] interface {
+	OK() T
+	Cancelled() T
+	Unknown() T
+	InvalidArgument() T
+	DeadlineExceeded() T
+	NotFound() T
+	AlreadyExists() T
+	PermissionDenied() T
+	ResourceExhausted() T
+	FailedPrecondition() T
+	Aborted() T
+	OutOfRange() T
+	Unimplemented() T
+	Internal() T
+	Unavailable() T
+	DataLoss() T
+	Unauthenticated() T
+}
+
+// VisitCode calls the method of given visitor corresponding to given code and returns its result.
+// A code that isn't one of the canonical codes is visited as CodeUnknown.
+func VisitCode[T any](code Code, visitor CodeVisitor[T]) T {
+	switch code {
+	case CodeOK:
+		return visitor.OK()
+	case CodeCancelled:
+		return visitor.Cancelled()
+	case CodeInvalidArgument:
+		return visitor.InvalidArgument()
+	case CodeDeadlineExceeded:
+		return visitor.DeadlineExceeded()
+	case CodeNotFound:
+		return visitor.NotFound()
+	case CodeAlreadyExists:
+		return visitor.AlreadyExists()
+	case CodePermissionDenied:
+		return visitor.PermissionDenied()
+	case CodeResourceExhausted:
+		return visitor.ResourceExhausted()
+	case CodeFailedPrecondition:
+		return visitor.FailedPrecondition()
+	case CodeAborted:
+		return visitor.Aborted()
+	case CodeOutOfRange:
+		return visitor.OutOfRange()
+	case CodeUnimplemented:
+		return visitor.Unimplemented()
+	case CodeInternal:
+		return visitor.Internal()
+	case CodeUnavailable:
+		return visitor.Unavailable()
+	case CodeDataLoss:
+		return visitor.DataLoss()
+	case CodeUnauthenticated:
+		return visitor.Unauthenticated()
+	default:
+		return visitor.Unknown()
+	}
+}
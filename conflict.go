@@ -0,0 +1,37 @@
+package opstatus
+
+import "strings"
+
+// DetailKeyConflictInfo is the detail key of the ConflictInfo attached by NewConflict.
+const DetailKeyConflictInfo = ReservedDetailKeyPrefix + "conflict_info"
+
+// ConflictInfo describes what changed when an optimistic-concurrency check failed.
+type ConflictInfo struct {
+	// ExpectedVersion is the version or ETag the client based its change on.
+	ExpectedVersion string `json:"expected_version,omitempty"`
+	// ActualVersion is the current version or ETag of the resource.
+	ActualVersion string `json:"actual_version,omitempty"`
+	// ConflictingFields are the paths of the fields changed concurrently, if known.
+	ConflictingFields []string `json:"conflicting_fields,omitempty"`
+}
+
+// NewConflict returns an Aborted status for an optimistic-concurrency failure, carrying given
+// ConflictInfo.
+func NewConflict(info ConflictInfo) *Status {
+	desc := "the resource was modified concurrently"
+	if info.ExpectedVersion != "" || info.ActualVersion != "" {
+		desc += ": expected version " + info.ExpectedVersion + ", actual version " + info.ActualVersion
+	}
+	if len(info.ConflictingFields) > 0 {
+		desc += "; conflicting fields: " + strings.Join(info.ConflictingFields, ", ")
+	}
+	status := StatusAborted.WithDescription(desc)
+	status.setDetail(DetailKeyConflictInfo, info)
+	return status
+}
+
+// ConflictInfo returns the ConflictInfo attached to this status, if any.
+func (s *Status) ConflictInfo() (ConflictInfo, bool) {
+	info, found := s.details[DetailKeyConflictInfo].(ConflictInfo)
+	return info, found
+}
@@ -0,0 +1,102 @@
+package opstatus
+
+import (
+	"log"
+	"sync"
+)
+
+// DetailKeyContractViolation is the detail key of the ContractViolation attached by
+// Contract.Enforce in strict mode.
+const DetailKeyContractViolation = ReservedDetailKeyPrefix + "contract_violation"
+
+// ContractViolation describes a status an operation returned without declaring it.
+type ContractViolation struct {
+	Operation string `json:"operation"`
+	Code      string `json:"code"`
+	Case      string `json:"case,omitempty"`
+}
+
+// Contract declares which codes and cases each operation of a service may return, so that the
+// published error contract of an API can be checked at runtime. It is safe for concurrent use.
+type Contract struct {
+	mu         sync.RWMutex
+	operations map[string]*operationContract
+	strict     bool
+}
+
+type operationContract struct {
+	codes map[Code]bool
+	cases map[string]bool
+}
+
+// NewContract returns an empty contract. In strict mode, e.g., in staging, Enforce converts the
+// undeclared statuses to Internal; otherwise it only logs them.
+func NewContract(strict bool) *Contract {
+	return &Contract{
+		operations: map[string]*operationContract{},
+		strict:     strict,
+	}
+}
+
+// Declare declares that given operation may return given codes, and cases if given. A status with
+// a case is allowed only if its case is declared; a status without a case only if its code is. OK
+// is always allowed.
+func (c *Contract) Declare(operation string, codes []Code, cases ...Case) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	op, found := c.operations[operation]
+	if !found {
+		op = &operationContract{codes: map[Code]bool{}, cases: map[string]bool{}}
+		c.operations[operation] = op
+	}
+	for _, code := range codes {
+		op.codes[code] = true
+	}
+	for _, theCase := range cases {
+		op.cases[theCase.Identifier()] = true
+	}
+}
+
+// Allows tells if given operation declared given status. Operations without any declaration allow
+// every status.
+func (c *Contract) Allows(operation string, status *Status) bool {
+	if status.IsOK() {
+		return true
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	op, found := c.operations[operation]
+	if !found {
+		return true
+	}
+	if status.theCase != nil {
+		return op.cases[status.theCase.Identifier()]
+	}
+	return op.codes[status.code]
+}
+
+// Enforce returns given status if given operation declared it. Otherwise, the violation is logged,
+// and in strict mode the status is converted to Internal carrying a ContractViolation detail, the
+// original status being kept as the cause.
+func (c *Contract) Enforce(operation string, status *Status) *Status {
+	if c.Allows(operation, status) {
+		return status
+	}
+	violation := ContractViolation{
+		Operation: operation,
+		Code:      status.code.Name(),
+	}
+	if status.theCase != nil {
+		violation.Case = status.theCase.Identifier()
+	}
+	log.Printf("[OpError] operation %s returned undeclared status %s\n", operation, status.ToErrorCondition())
+	if !c.strict {
+		return status
+	}
+	// The original status is wrapped without NewOpError, so that the observers only see the
+	// converted status once it is returned as an error, rather than both.
+	original := &OpError{status: status.derive()}
+	converted := StatusInternal.WithDescriptionf("operation %s returned an undeclared status", operation).WithCause(original)
+	converted.setDetail(DetailKeyContractViolation, violation)
+	return converted
+}
@@ -0,0 +1,27 @@
+package opstatus
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStrictEnforceDoesNotNotifyTheObservers(t *testing.T) {
+	var observed []*Status
+	stop := Observe(func(s *Status) { observed = append(observed, s) })
+	defer stop()
+
+	contract := NewContract(true)
+	contract.Declare("GetOrder", []Code{CodeNotFound})
+	converted := contract.Enforce("GetOrder", StatusUnavailable.WithDescription("inventory is down"))
+
+	if len(observed) != 0 {
+		t.Errorf("Enforce notified the observers of %v", observed)
+	}
+	if converted.Code() != CodeInternal {
+		t.Errorf("Code() = %v, want Internal", converted.Code())
+	}
+	var original *OpError
+	if !errors.As(converted.Cause(), &original) || original.Status().Code() != CodeUnavailable {
+		t.Errorf("Cause() = %v, want the original status", converted.Cause())
+	}
+}
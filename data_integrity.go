@@ -0,0 +1,42 @@
+package opstatus
+
+import "fmt"
+
+// DetailKeyDataIntegrityInfo is the detail key of the DataIntegrityInfo attached by
+// NewDataIntegrityLoss.
+const DetailKeyDataIntegrityInfo = ReservedDetailKeyPrefix + "data_integrity_info"
+
+// ByteRange is a range of bytes [Start, End).
+type ByteRange struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+// DataIntegrityInfo describes a detected data corruption, for repair tooling.
+type DataIntegrityInfo struct {
+	// Object identifies the stored object, e.g., a bucket and key or a file path.
+	Object string `json:"object"`
+	// ChecksumAlgorithm is the algorithm of the checksums, e.g., crc32c or sha256.
+	ChecksumAlgorithm string `json:"checksum_algorithm,omitempty"`
+	ExpectedChecksum  string `json:"expected_checksum,omitempty"`
+	ActualChecksum    string `json:"actual_checksum,omitempty"`
+	// CorruptRanges are the byte ranges found corrupt, if known.
+	CorruptRanges []ByteRange `json:"corrupt_ranges,omitempty"`
+}
+
+// NewDataIntegrityLoss returns a DataLoss status carrying given DataIntegrityInfo.
+func NewDataIntegrityLoss(info DataIntegrityInfo) *Status {
+	desc := fmt.Sprintf("data integrity check failed for %s", info.Object)
+	if info.ExpectedChecksum != "" || info.ActualChecksum != "" {
+		desc += fmt.Sprintf(": expected %s checksum %s, got %s", info.ChecksumAlgorithm, info.ExpectedChecksum, info.ActualChecksum)
+	}
+	status := StatusDataLoss.WithDescription(desc)
+	status.setDetail(DetailKeyDataIntegrityInfo, info)
+	return status
+}
+
+// DataIntegrityInfo returns the DataIntegrityInfo attached to this status, if any.
+func (s *Status) DataIntegrityInfo() (DataIntegrityInfo, bool) {
+	info, found := s.details[DetailKeyDataIntegrityInfo].(DataIntegrityInfo)
+	return info, found
+}
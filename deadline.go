@@ -0,0 +1,97 @@
+package opstatus
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DetailKeyDeadlineInfo is the detail key of the DeadlineInfo attached to DeadlineExceeded statuses
+// produced by DeadlineExceededFromContext.
+const DetailKeyDeadlineInfo = ReservedDetailKeyPrefix + "deadline_info"
+
+// DeadlineInfo tells how the deadline budget of an operation was consumed.
+type DeadlineInfo struct {
+	// Hop is the name of the hop that was running when the budget ran out.
+	Hop string `json:"hop,omitempty"`
+	// Budget is the total budget of the operation.
+	Budget time.Duration `json:"budget"`
+	// HopBudget is the part of the budget granted to the hop.
+	HopBudget time.Duration `json:"hop_budget,omitempty"`
+	// Elapsed is the time spent since the budget was set.
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+type budgetKey struct{}
+
+type deadlineBudget struct {
+	start     time.Time
+	total     time.Duration
+	hop       string
+	hopBudget time.Duration
+}
+
+// WithDeadlineBudget returns a copy of the parent context that expires after the given total budget
+// (or earlier, if the parent expires earlier). Hops of the operation derive their own, shorter
+// deadlines from the budget with WithHopDeadline.
+func WithDeadlineBudget(parent context.Context, total time.Duration) (context.Context, context.CancelFunc) {
+	budget := &deadlineBudget{
+		start: now(),
+		total: total,
+	}
+	ctx, cancel := context.WithTimeout(parent, total)
+	return context.WithValue(ctx, budgetKey{}, budget), cancel
+}
+
+// WithHopDeadline returns a copy of the parent context for the named hop, e.g., a downstream call,
+// that expires after the given fraction of the remaining budget. Fractions outside (0, 1] grant the
+// whole remaining budget. If the parent has no deadline, the hop is only recorded.
+func WithHopDeadline(parent context.Context, hop string, fraction float64) (context.Context, context.CancelFunc) {
+	parentBudget, _ := parent.Value(budgetKey{}).(*deadlineBudget)
+	budget := &deadlineBudget{
+		start: now(),
+		hop:   hop,
+	}
+	if parentBudget != nil {
+		budget.start = parentBudget.start
+		budget.total = parentBudget.total
+	}
+
+	deadline, hasDeadline := parent.Deadline()
+	if !hasDeadline {
+		ctx, cancel := context.WithCancel(parent)
+		return context.WithValue(ctx, budgetKey{}, budget), cancel
+	}
+	// Context deadlines are on the wall clock, whatever the package clock, which only measures the
+	// elapsed time.
+	remaining := time.Until(deadline)
+	if fraction > 0 && fraction < 1 {
+		remaining = time.Duration(float64(remaining) * fraction)
+	}
+	budget.hopBudget = remaining
+	ctx, cancel := context.WithTimeout(parent, remaining)
+	return context.WithValue(ctx, budgetKey{}, budget), cancel
+}
+
+// DeadlineExceededFromContext returns a DeadlineExceeded status carrying a DeadlineInfo detail that
+// names the hop that consumed the budget if given context has expired. Otherwise, it returns nil.
+func DeadlineExceededFromContext(ctx context.Context) *Status {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil
+	}
+	status := StatusDeadlineExceeded.WithDescription("deadline budget exhausted")
+	budget, _ := ctx.Value(budgetKey{}).(*deadlineBudget)
+	if budget == nil {
+		return status
+	}
+	if budget.hop != "" {
+		status = status.WithDescriptionf("deadline budget exhausted in %s", budget.hop)
+	}
+	status.setDetail(DetailKeyDeadlineInfo, DeadlineInfo{
+		Hop:       budget.hop,
+		Budget:    budget.total,
+		HopBudget: budget.hopBudget,
+		Elapsed:   since(budget.start),
+	})
+	return status
+}
@@ -0,0 +1,29 @@
+package opstatus
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fixedClock struct{ systemClock }
+
+func (fixedClock) Now() time.Time {
+	return time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestHopDeadlineIgnoresThePackageClock(t *testing.T) {
+	t.Cleanup(func() { SetClock(nil) })
+	if err := SetClock(fixedClock{}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	hopCtx, hopCancel := WithHopDeadline(ctx, "inventory", 0.5)
+	defer hopCancel()
+	deadline, _ := hopCtx.Deadline()
+	if remaining := time.Until(deadline); remaining > 500*time.Millisecond || remaining < 400*time.Millisecond {
+		t.Errorf("hop deadline in %v, want about 500ms", remaining)
+	}
+}
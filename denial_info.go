@@ -0,0 +1,40 @@
+package opstatus
+
+// DetailKeyDenialInfo is the detail key of the DenialInfo attached by NewPermissionDenied.
+const DetailKeyDenialInfo = ReservedDetailKeyPrefix + "denial_info"
+
+// DenialInfo tells why an authorization decision denied an operation.
+type DenialInfo struct {
+	// Permission is the permission the caller is missing, e.g., "orders.delete".
+	Permission string `json:"permission,omitempty"`
+	// Role is the role that would grant the permission, if relevant.
+	Role string `json:"role,omitempty"`
+	// Policy is the name of the policy that denied the operation. It is left out of the status if
+	// PolicyPrivate is set.
+	Policy string `json:"policy,omitempty"`
+	// PolicyPrivate marks the policy as an internal that must not be exposed to clients.
+	PolicyPrivate bool `json:"-"`
+	// DecisionID identifies the decision in the audit logs of the authorization system.
+	DecisionID string `json:"decision_id,omitempty"`
+}
+
+// NewPermissionDenied returns a PermissionDenied status carrying given DenialInfo. The policy name
+// is dropped if it is marked private.
+func NewPermissionDenied(info DenialInfo) *Status {
+	if info.PolicyPrivate {
+		info.Policy = ""
+	}
+	desc := "permission denied"
+	if info.Permission != "" {
+		desc = "missing permission " + info.Permission
+	}
+	status := StatusPermissionDenied.WithDescription(desc)
+	status.setDetail(DetailKeyDenialInfo, info)
+	return status
+}
+
+// DenialInfo returns the DenialInfo attached to this status, if any.
+func (s *Status) DenialInfo() (DenialInfo, bool) {
+	info, found := s.details[DetailKeyDenialInfo].(DenialInfo)
+	return info, found
+}
@@ -0,0 +1,27 @@
+package opstatus
+
+// DetailKeyDependency is the detail key of the DependencyInfo recorded by WithDependency.
+const DetailKeyDependency = ReservedDetailKeyPrefix + "dependency"
+
+// DependencyInfo identifies the downstream dependency that produced a failure.
+type DependencyInfo struct {
+	Name     string `json:"name"`
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// WithDependency returns a derived instance of this Status recording which downstream dependency
+// produced the failure, so that, e.g., an Unavailable status tells which dependency is unavailable.
+func (s *Status) WithDependency(name, endpoint string) *Status {
+	derived := s.derive()
+	derived.setDetail(DetailKeyDependency, DependencyInfo{
+		Name:     name,
+		Endpoint: endpoint,
+	})
+	return derived
+}
+
+// Dependency returns the downstream dependency recorded by WithDependency, if any.
+func (s *Status) Dependency() (DependencyInfo, bool) {
+	dependency, found := s.details[DetailKeyDependency].(DependencyInfo)
+	return dependency, found
+}
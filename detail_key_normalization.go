@@ -0,0 +1,75 @@
+package opstatus
+
+import (
+	"strings"
+	"sync/atomic"
+	"unicode"
+)
+
+// KeyNormalization is a set of rules applied to detail keys, so that producers and consumers agree
+// on key naming. Keys are always trimmed.
+type KeyNormalization uint32
+
+const (
+	// LowercaseKeys lowercases detail keys, e.g., "OrderID" becomes "orderid".
+	LowercaseKeys KeyNormalization = 1 << iota
+	// SnakeCaseKeys converts detail keys to snake_case, e.g., "orderID" and "order-id" become
+	// "order_id".
+	SnakeCaseKeys
+)
+
+var keyNormalization atomic.Uint32
+
+// SetDetailKeyNormalization sets the normalization applied to detail keys by AddDetail, WithDetails
+// and Detail. It is package-level configuration, meant to be set during initialization. It returns
+// ErrFrozen once the configuration is frozen.
+func SetDetailKeyNormalization(normalization KeyNormalization) error {
+	if err := checkNotFrozen(); err != nil {
+		return err
+	}
+	keyNormalization.Store(uint32(normalization))
+	return nil
+}
+
+// NormalizeDetailKey applies the configured normalization to given key. Decoders should apply it to
+// the keys they read, so that lookups don't fail on naming differences. Reserved keys are kept as
+// they are.
+func NormalizeDetailKey(key string) string {
+	key = strings.TrimSpace(key)
+	if isReservedDetailKey(key) {
+		return key
+	}
+	normalization := KeyNormalization(keyNormalization.Load())
+	if normalization&SnakeCaseKeys != 0 {
+		return snakeCase(key)
+	}
+	if normalization&LowercaseKeys != 0 {
+		return strings.ToLower(key)
+	}
+	return key
+}
+
+// Detail returns the detail with given key, normalized the same way as at insertion.
+func (s *Status) Detail(key string) (any, bool) {
+	value, found := s.details[NormalizeDetailKey(key)]
+	return value, found
+}
+
+func snakeCase(key string) string {
+	runes := []rune(key)
+	var b strings.Builder
+	for i, r := range runes {
+		if r == '-' || unicode.IsSpace(r) {
+			r = '_'
+		}
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package opstatus
+
+import "strings"
+
+// ReservedDetailKeyPrefix namespaces the detail keys of the built-in typed details, e.g.,
+// DetailKeyDependency. Applications can't add details under it, so they can't overwrite the
+// built-in details by accident.
+const ReservedDetailKeyPrefix = "opstatus.io/"
+
+// legacyDetailKeys maps the flat keys built-in details used to have to their namespaced keys.
+var legacyDetailKeys = map[string]string{
+	"deadline_info": DetailKeyDeadlineInfo,
+	"dependency":    DetailKeyDependency,
+}
+
+func isReservedDetailKey(key string) bool {
+	return strings.HasPrefix(key, ReservedDetailKeyPrefix)
+}
+
+// MigrateLegacyDetailKeys returns a derived instance of this Status whose built-in details stored
+// under their former flat keys, e.g., "dependency", are moved to their namespaced keys. It helps
+// consumers of statuses produced by older versions.
+func (s *Status) MigrateLegacyDetailKeys() *Status {
+	migrated := s.derive()
+	for legacyKey, key := range legacyDetailKeys {
+		value, found := migrated.details[legacyKey]
+		if !found {
+			continue
+		}
+		delete(migrated.details, legacyKey)
+		if _, exists := migrated.details[key]; !exists {
+			migrated.details[key] = value
+		}
+	}
+	return migrated
+}
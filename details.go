@@ -0,0 +1,46 @@
+package opstatus
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// badDetailKey is the key given to detail values WithDetails can't pair with a string key, as
+// log/slog does.
+const badDetailKey = "!BADKEY"
+
+var strictDetails atomic.Bool
+
+// SetStrictDetails enables or disables the strict mode of WithDetails, in which malformed key-value
+// pairs panic instead of being recorded under "!BADKEY". It is meant for tests and development. It
+// returns ErrFrozen once the configuration is frozen.
+func SetStrictDetails(strict bool) error {
+	if err := checkNotFrozen(); err != nil {
+		return err
+	}
+	strictDetails.Store(strict)
+	return nil
+}
+
+// WithDetails returns a derived instance of this Status with given details added, given as
+// alternating keys and values in the style of log/slog:
+//
+//	StatusNotFound.WithDetails("order_id", id, "sku", sku)
+//
+// A value without a string key is recorded under "!BADKEY", unless the strict mode is enabled.
+func (s *Status) WithDetails(keysAndValues ...any) *Status {
+	derived := s.derive()
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, isString := keysAndValues[i].(string)
+		if !isString || i+1 == len(keysAndValues) {
+			if strictDetails.Load() {
+				panic(fmt.Sprintf("opstatus: WithDetails expects string keys paired with values, got %v at %d", keysAndValues[i], i))
+			}
+			derived.AddDetail(badDetailKey, keysAndValues[i])
+			i--
+			continue
+		}
+		derived.AddDetail(key, keysAndValues[i+1])
+	}
+	return derived
+}
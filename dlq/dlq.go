@@ -0,0 +1,127 @@
+package dlq
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/ikonglong/op-status"
+)
+
+// Action is what a message consumer should do with a message whose processing failed.
+type Action string
+
+const (
+	// ActionRetry means the message should be redelivered.
+	ActionRetry = Action("retry")
+
+	// ActionPark means the message should be moved to the dead-letter queue for later inspection.
+	ActionPark = Action("park")
+
+	// ActionDrop means the message should be discarded.
+	ActionDrop = Action("drop")
+)
+
+func (a Action) valid() bool {
+	return a == ActionRetry || a == ActionPark || a == ActionDrop
+}
+
+// Policy configures the action taken for failure statuses. A rule for the case of a status takes
+// precedence over a rule for its code. If no rule applies, Default is used, and if Default is
+// empty, transient failures are retried and the others are parked. Its JSON document looks like:
+//
+//	{"default": "park", "by_code": {"ServiceUnavailable": "retry"}, "by_case": {"duplicate_order": "drop"}}
+type Policy struct {
+	Default Action            `json:"default,omitempty"`
+	ByCode  map[string]Action `json:"by_code,omitempty"` // keyed by code name, e.g., "ServiceUnavailable"
+	ByCase  map[string]Action `json:"by_case,omitempty"` // keyed by case identifier
+}
+
+// Validate tells if this policy refers only to known actions and codes.
+func (p *Policy) Validate() error {
+	if p.Default != "" && !p.Default.valid() {
+		return fmt.Errorf("unknown default action %q", p.Default)
+	}
+	for name, action := range p.ByCode {
+		if _, found := opstatus.CodeByName(name); !found {
+			return fmt.Errorf("unknown code %q", name)
+		}
+		if !action.valid() {
+			return fmt.Errorf("unknown action %q for code %q", action, name)
+		}
+	}
+	for id, action := range p.ByCase {
+		if !action.valid() {
+			return fmt.Errorf("unknown action %q for case %q", action, id)
+		}
+	}
+	return nil
+}
+
+// LoadPolicy reads a policy from given JSON document and validates it.
+func LoadPolicy(r io.Reader) (Policy, error) {
+	var policy Policy
+	if err := json.NewDecoder(r).Decode(&policy); err != nil {
+		return Policy{}, fmt.Errorf("decode dlq policy: %w", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return Policy{}, fmt.Errorf("invalid dlq policy: %w", err)
+	}
+	return policy, nil
+}
+
+// Router decides retry vs park vs drop for failure statuses and counts its decisions. It is safe
+// for concurrent use.
+type Router struct {
+	policy  Policy
+	retried atomic.Uint64
+	parked  atomic.Uint64
+	dropped atomic.Uint64
+}
+
+// NewRouter returns a Router applying given policy.
+func NewRouter(policy Policy) *Router {
+	return &Router{policy: policy}
+}
+
+// Route returns the action to take for a message that failed with given status.
+func (r *Router) Route(status *opstatus.Status) Action {
+	action := r.decide(status)
+	switch action {
+	case ActionRetry:
+		r.retried.Add(1)
+	case ActionPark:
+		r.parked.Add(1)
+	case ActionDrop:
+		r.dropped.Add(1)
+	}
+	return action
+}
+
+func (r *Router) decide(status *opstatus.Status) Action {
+	if theCase := status.TheCase(); theCase != nil {
+		if action, found := r.policy.ByCase[theCase.Identifier()]; found {
+			return action
+		}
+	}
+	if action, found := r.policy.ByCode[status.Code().Name()]; found {
+		return action
+	}
+	if r.policy.Default != "" {
+		return r.policy.Default
+	}
+	if status.IsTransient() {
+		return ActionRetry
+	}
+	return ActionPark
+}
+
+// Counts returns how many times each action has been decided by this router.
+func (r *Router) Counts() map[Action]uint64 {
+	return map[Action]uint64{
+		ActionRetry: r.retried.Load(),
+		ActionPark:  r.parked.Load(),
+		ActionDrop:  r.dropped.Load(),
+	}
+}
@@ -0,0 +1,19 @@
+package dlq
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ikonglong/op-status"
+)
+
+func TestDocumentedPolicyIsValid(t *testing.T) {
+	policy, err := LoadPolicy(strings.NewReader(
+		`{"default": "park", "by_code": {"ServiceUnavailable": "retry"}, "by_case": {"duplicate_order": "drop"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := NewRouter(policy).Route(opstatus.StatusUnavailable.WithDescription("broker down")); got != ActionRetry {
+		t.Errorf("Route(Unavailable) = %q, want %q", got, ActionRetry)
+	}
+}
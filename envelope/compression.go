@@ -0,0 +1,91 @@
+package envelope
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Encoding is a compression of the details of an envelope, named like HTTP content codings.
+type Encoding string
+
+const (
+	Gzip    = Encoding("gzip")
+	Deflate = Encoding("deflate")
+)
+
+// maxDetailsSize bounds the decompressed details, so that a small envelope can't expand into an
+// exhausting amount of memory.
+const maxDetailsSize = 16 << 20
+
+// compressDetails moves the details of the encoded status of this envelope into Details, compressed
+// with given encoding, if their encoding is longer than threshold bytes.
+func (e *Envelope) compressDetails(threshold int, encoding Encoding) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(e.Status, &members); err != nil {
+		return fmt.Errorf("encode status: %w", err)
+	}
+	details, found := members["details"]
+	if !found || len(details) <= threshold {
+		return nil
+	}
+
+	var compressed bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case Gzip:
+		w = gzip.NewWriter(&compressed)
+	case Deflate:
+		w, _ = flate.NewWriter(&compressed, flate.DefaultCompression)
+	default:
+		return fmt.Errorf("unsupported details encoding %q", encoding)
+	}
+	if _, err := w.Write(details); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	delete(members, "details")
+	status, err := json.Marshal(members)
+	if err != nil {
+		return err
+	}
+	e.Status, e.DetailsEncoding, e.Details = status, encoding, compressed.Bytes()
+	return nil
+}
+
+// decompressDetails returns the encoded status of this envelope with its decompressed details.
+func (e *Envelope) decompressDetails() (json.RawMessage, error) {
+	var r io.Reader
+	switch e.DetailsEncoding {
+	case Gzip:
+		gr, err := gzip.NewReader(bytes.NewReader(e.Details))
+		if err != nil {
+			return nil, fmt.Errorf("decompress status details: %w", err)
+		}
+		r = gr
+	case Deflate:
+		r = flate.NewReader(bytes.NewReader(e.Details))
+	default:
+		return nil, fmt.Errorf("unsupported details encoding %q", e.DetailsEncoding)
+	}
+	details, err := io.ReadAll(io.LimitReader(r, maxDetailsSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("decompress status details: %w", err)
+	}
+	if len(details) > maxDetailsSize {
+		return nil, fmt.Errorf("status details exceed %d bytes", maxDetailsSize)
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(e.Status, &members); err != nil {
+		return nil, fmt.Errorf("decode status envelope: %w", err)
+	}
+	members["details"] = details
+	return json.Marshal(members)
+}
@@ -0,0 +1,71 @@
+package envelope
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/ikonglong/op-status"
+)
+
+func TestLargeDetailsAreCompressedTransparently(t *testing.T) {
+	violations := make([]opstatus.FieldViolation, 200)
+	for i := range violations {
+		violations[i] = opstatus.FieldViolation{Field: fmt.Sprintf("/items/%d/sku", i), Description: "is unknown"}
+	}
+	status := opstatus.NewBadRequest(violations...)
+	keyring := NewKeyring()
+	if err := keyring.AddHMACKey("k1", bytes.Repeat([]byte{1}, 32)); err != nil {
+		t.Fatal(err)
+	}
+	if err := keyring.UseForSigning("k1"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, encoding := range []Encoding{Gzip, Deflate} {
+		t.Run(string(encoding), func(t *testing.T) {
+			plain, err := New(status)
+			if err != nil {
+				t.Fatal(err)
+			}
+			compressed, err := New(status, CompressDetailsAbove(1024, encoding))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if compressed.DetailsEncoding != encoding || len(compressed.Details)+len(compressed.Status) >= len(plain.Status)/4 {
+				t.Errorf("envelope = %s with %d compressed bytes, want the details compressed", compressed.Status, len(compressed.Details))
+			}
+			if err := keyring.Sign(compressed); err != nil {
+				t.Fatal(err)
+			}
+			value, err := compressed.HeaderValue()
+			if err != nil {
+				t.Fatal(err)
+			}
+			received, err := ParseHeader(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := keyring.Verify(received)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := decoded.FieldViolations(); len(got) != len(violations) || got[199] != violations[199] {
+				t.Errorf("decoded %d violations, want %d", len(got), len(violations))
+			}
+
+			received.Details[len(received.Details)/2] ^= 0xff
+			if _, err := keyring.Verify(received); err == nil {
+				t.Error("Verify() accepted tampered details")
+			}
+		})
+	}
+
+	small, err := New(opstatus.StatusNotFound.WithDetails("order_id", "o-1"), CompressDetailsAbove(1024, Gzip))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if small.DetailsEncoding != "" {
+		t.Errorf("details under the threshold were compressed")
+	}
+}
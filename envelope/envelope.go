@@ -0,0 +1,269 @@
+// Package envelope wraps encoded statuses into envelopes carried in a response body or in the
+// Op-Status-Envelope header, optionally signed with HMAC-SHA256 or Ed25519, so that a gateway can
+// verify that a status claimed to come from an internal service wasn't forged by an intermediary,
+// and optionally with their details compressed, e.g., for the large violation lists of batch APIs.
+package envelope
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ikonglong/op-status"
+)
+
+// Header is the HTTP header carrying an envelope, encoded by HeaderValue.
+const Header = "Op-Status-Envelope"
+
+// Algorithm is a signature algorithm, named like in JSON Web Signatures.
+type Algorithm string
+
+const (
+	HMACSHA256 = Algorithm("HS256")
+	Ed25519    = Algorithm("EdDSA")
+)
+
+var (
+	// ErrUnsigned is returned by Verify for envelopes without signature.
+	ErrUnsigned = errors.New("status envelope is not signed")
+	// ErrUnknownKey is returned by Verify for envelopes signed with a key missing from the keyring,
+	// e.g., a retired one.
+	ErrUnknownKey = errors.New("status envelope is signed with an unknown key")
+	// ErrInvalidSignature is returned by Verify for envelopes whose signature doesn't match.
+	ErrInvalidSignature = errors.New("status envelope signature is invalid")
+)
+
+// Envelope is an encoded status with its signature, if signed.
+type Envelope struct {
+	// Status is the JSON encoding of the status, without its details if they are compressed.
+	Status json.RawMessage `json:"status"`
+	// DetailsEncoding is the compression of Details, if the details are compressed.
+	DetailsEncoding Encoding `json:"details_enc,omitempty"`
+	// Details is the compressed JSON encoding of the details of the status.
+	Details   []byte    `json:"details,omitempty"`
+	KeyID     string    `json:"kid,omitempty"`
+	Algorithm Algorithm `json:"alg,omitempty"`
+	Signature []byte    `json:"sig,omitempty"`
+}
+
+// Option customizes the envelopes made by New.
+type Option func(o *options)
+
+type options struct {
+	threshold int
+	encoding  Encoding
+}
+
+// CompressDetailsAbove makes New compress the details of the statuses with given encoding when
+// their JSON encoding is longer than threshold bytes.
+func CompressDetailsAbove(threshold int, encoding Encoding) Option {
+	return func(o *options) {
+		o.threshold, o.encoding = threshold, encoding
+	}
+}
+
+// New returns an unsigned envelope of given status.
+func New(status *opstatus.Status, opts ...Option) (*Envelope, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	encoded, err := json.Marshal(status)
+	if err != nil {
+		return nil, fmt.Errorf("encode status: %w", err)
+	}
+	e := &Envelope{Status: encoded}
+	if o.encoding != "" {
+		if err := e.compressDetails(o.threshold, o.encoding); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// Decode returns the status of this envelope without verifying its signature. Compressed details
+// are decompressed.
+func (e *Envelope) Decode() (*opstatus.Status, error) {
+	encoded := e.Status
+	if e.DetailsEncoding != "" {
+		var err error
+		if encoded, err = e.decompressDetails(); err != nil {
+			return nil, err
+		}
+	}
+	status := &opstatus.Status{}
+	if err := json.Unmarshal(encoded, status); err != nil {
+		return nil, fmt.Errorf("decode status envelope: %w", err)
+	}
+	return status, nil
+}
+
+// HeaderValue encodes this envelope for the Header, as base64url-encoded JSON.
+func (e *Envelope) HeaderValue() (string, error) {
+	encoded, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(encoded), nil
+}
+
+// ParseHeader decodes an envelope encoded by HeaderValue.
+func ParseHeader(value string) (*Envelope, error) {
+	encoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("decode status envelope header: %w", err)
+	}
+	e := &Envelope{}
+	if err := json.Unmarshal(encoded, e); err != nil {
+		return nil, fmt.Errorf("decode status envelope header: %w", err)
+	}
+	return e, nil
+}
+
+// signingInput returns what the signature of this envelope covers: the key and algorithm, so that
+// they can't be swapped, and the encoded status with its compressed details, if any.
+func (e *Envelope) signingInput() []byte {
+	input, _ := json.Marshal([]any{e.Algorithm, e.KeyID, e.DetailsEncoding, e.Details, e.Status})
+	return input
+}
+
+type key struct {
+	algorithm Algorithm
+	secret    []byte
+	private   ed25519.PrivateKey
+	public    ed25519.PublicKey
+}
+
+// Keyring holds the keys envelopes are signed and verified with. Keys are rotated by adding the
+// new key, switching to it with UseForSigning once the verifiers have it, and removing the old one
+// once the envelopes it signed are gone. It is safe for concurrent use.
+type Keyring struct {
+	mu           sync.RWMutex
+	keys         map[string]key
+	signingKeyID string
+}
+
+// NewKeyring returns an empty keyring.
+func NewKeyring() *Keyring {
+	return &Keyring{keys: map[string]key{}}
+}
+
+// AddHMACKey adds an HMAC-SHA256 key with given ID, which both signs and verifies.
+func (k *Keyring) AddHMACKey(id string, secret []byte) error {
+	if len(secret) < sha256.Size {
+		return fmt.Errorf("HMAC key %q is shorter than %d bytes", id, sha256.Size)
+	}
+	return k.add(id, key{algorithm: HMACSHA256, secret: secret})
+}
+
+// AddEd25519Key adds an Ed25519 private key with given ID, which both signs and verifies.
+func (k *Keyring) AddEd25519Key(id string, private ed25519.PrivateKey) error {
+	if len(private) != ed25519.PrivateKeySize {
+		return fmt.Errorf("Ed25519 private key %q has %d bytes", id, len(private))
+	}
+	return k.add(id, key{algorithm: Ed25519, private: private, public: private.Public().(ed25519.PublicKey)})
+}
+
+// AddEd25519PublicKey adds an Ed25519 public key with given ID, which only verifies, e.g., in a
+// gateway.
+func (k *Keyring) AddEd25519PublicKey(id string, public ed25519.PublicKey) error {
+	if len(public) != ed25519.PublicKeySize {
+		return fmt.Errorf("Ed25519 public key %q has %d bytes", id, len(public))
+	}
+	return k.add(id, key{algorithm: Ed25519, public: public})
+}
+
+func (k *Keyring) add(id string, added key) error {
+	if id == "" {
+		return errors.New("key ID is empty")
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, found := k.keys[id]; found {
+		return fmt.Errorf("key %q is already in the keyring", id)
+	}
+	k.keys[id] = added
+	return nil
+}
+
+// UseForSigning makes Sign use the key with given ID, which must be able to sign.
+func (k *Keyring) UseForSigning(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	signing, found := k.keys[id]
+	if !found {
+		return fmt.Errorf("key %q is not in the keyring", id)
+	}
+	if signing.algorithm == Ed25519 && signing.private == nil {
+		return fmt.Errorf("key %q is a public key", id)
+	}
+	k.signingKeyID = id
+	return nil
+}
+
+// RemoveKey removes the key with given ID, e.g., a retired one. Envelopes signed with it no longer
+// verify.
+func (k *Keyring) RemoveKey(id string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.keys, id)
+	if k.signingKeyID == id {
+		k.signingKeyID = ""
+	}
+}
+
+// Sign signs given envelope with the key chosen by UseForSigning.
+func (k *Keyring) Sign(e *Envelope) error {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if k.signingKeyID == "" {
+		return errors.New("no key is used for signing")
+	}
+	signing := k.keys[k.signingKeyID]
+	e.KeyID, e.Algorithm = k.signingKeyID, signing.algorithm
+	switch signing.algorithm {
+	case HMACSHA256:
+		e.Signature = signHMAC(signing.secret, e.signingInput())
+	case Ed25519:
+		e.Signature = ed25519.Sign(signing.private, e.signingInput())
+	}
+	return nil
+}
+
+// Verify returns the status of given envelope if it is signed with a key of this keyring.
+func (k *Keyring) Verify(e *Envelope) (*opstatus.Status, error) {
+	if e.Signature == nil {
+		return nil, ErrUnsigned
+	}
+	k.mu.RLock()
+	verifying, found := k.keys[e.KeyID]
+	k.mu.RUnlock()
+	if !found {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, e.KeyID)
+	}
+	if e.Algorithm != verifying.algorithm {
+		return nil, ErrInvalidSignature
+	}
+	var valid bool
+	switch verifying.algorithm {
+	case HMACSHA256:
+		valid = hmac.Equal(e.Signature, signHMAC(verifying.secret, e.signingInput()))
+	case Ed25519:
+		valid = ed25519.Verify(verifying.public, e.signingInput(), e.Signature)
+	}
+	if !valid {
+		return nil, ErrInvalidSignature
+	}
+	return e.Decode()
+}
+
+func signHMAC(secret, input []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(input)
+	return mac.Sum(nil)
+}
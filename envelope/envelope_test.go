@@ -0,0 +1,99 @@
+package envelope
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	"github.com/ikonglong/op-status"
+)
+
+func TestSignedEnvelopesVerifyThroughTheHeader(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, gateway := NewKeyring(), NewKeyring()
+	if err := signer.AddEd25519Key("2024-01", private); err != nil {
+		t.Fatal(err)
+	}
+	if err := signer.UseForSigning("2024-01"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gateway.AddEd25519PublicKey("2024-01", public); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := New(opstatus.StatusNotFound.WithDescription("order not found"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := signer.Sign(e); err != nil {
+		t.Fatal(err)
+	}
+	value, err := e.HeaderValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	received, err := ParseHeader(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := gateway.Verify(received)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Code() != opstatus.CodeNotFound || status.Description() != "order not found" {
+		t.Errorf("Verify() = %v %q, want the signed status", status.Code(), status.Description())
+	}
+
+	received.Status = bytes.Replace(received.Status, []byte("order not found"), []byte("order was paid"), 1)
+	if _, err := gateway.Verify(received); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() of a forged status = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	keyring := NewKeyring()
+	for _, id := range []string{"old", "new"} {
+		if err := keyring.AddHMACKey(id, bytes.Repeat([]byte(id), 32)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sign := func(id string) *Envelope {
+		t.Helper()
+		if err := keyring.UseForSigning(id); err != nil {
+			t.Fatal(err)
+		}
+		e, err := New(opstatus.StatusUnavailable.WithDescription("busy"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := keyring.Sign(e); err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+	signedWithOld, signedWithNew := sign("old"), sign("new")
+	if _, err := keyring.Verify(signedWithOld); err != nil {
+		t.Errorf("Verify() of an envelope signed with the previous key = %v", err)
+	}
+
+	keyring.RemoveKey("old")
+	if _, err := keyring.Verify(signedWithOld); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Verify() of an envelope signed with a removed key = %v, want ErrUnknownKey", err)
+	}
+	if _, err := keyring.Verify(signedWithNew); err != nil {
+		t.Errorf("Verify() of an envelope signed with the current key = %v", err)
+	}
+
+	signedWithNew.KeyID = "old"
+	if _, err := keyring.Verify(signedWithNew); err == nil {
+		t.Error("Verify() accepted an envelope whose key ID was changed")
+	}
+	unsigned, _ := New(&opstatus.StatusUnavailable)
+	if _, err := keyring.Verify(unsigned); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("Verify() of an unsigned envelope = %v, want ErrUnsigned", err)
+	}
+}
@@ -0,0 +1,42 @@
+package opstatus
+
+import "reflect"
+
+// CaseEqual tells if given cases are deeply equal. Unlike ==, it doesn't panic on cases of
+// non-comparable types, e.g., struct-based cases with slice fields.
+func CaseEqual(a, b Case) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Identifier() == b.Identifier() && reflect.DeepEqual(a, b)
+}
+
+// DeepEqual tells if this status and given one have the same code, deeply equal cases, the same
+// description, deeply equal details and the same transience. Causes are compared with ==, like
+// errors.Is does.
+func (s *Status) DeepEqual(other *Status) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return s.code == other.code &&
+		CaseEqual(s.theCase, other.theCase) &&
+		s.description == other.description &&
+		detailsEqual(s.details, other.details) &&
+		s.Transience() == other.Transience() &&
+		causeEqual(s.cause, other.cause)
+}
+
+func causeEqual(a, b error) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return reflect.TypeOf(a).Comparable() && a == b
+}
+
+// detailsEqual tells if given details are deeply equal, considering nil and empty details equal.
+func detailsEqual(a, b map[string]any) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -2,64 +2,79 @@ package error
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
 
 	"github.com/ikonglong/op-status"
 )
 
-type OpError struct {
-	cause  error
-	status *opstatus.Status
-}
+// OpError is an error reporting the Status of a failed operation.
+type OpError = opstatus.OpError
 
 func NewWithStatus(status opstatus.Status) *OpError {
-	return &OpError{
-		status: &status,
-	}
+	return opstatus.NewOpError(status)
 }
 
+// NewWithStatusAndCause returns an OpError with given status caused by given error. A nil cause
+// keeps the cause the status already has, if any.
 func NewWithStatusAndCause(status opstatus.Status, cause error) *OpError {
-	return &OpError{
-		status: &status,
-		cause:  cause,
+	if cause == nil {
+		return opstatus.NewOpError(status)
 	}
+	return opstatus.NewOpError(*status.WithCause(cause))
 }
 
-func (e *OpError) Status() *opstatus.Status {
-	return e.status
+// Wrap returns an OpError with given status caused by given error. Like the other constructors,
+// it takes the status first.
+func Wrap(status opstatus.Status, cause error) *OpError {
+	return NewWithStatusAndCause(status, cause)
 }
 
-func (e *OpError) Cause() error {
-	return e.cause
-}
-
-func (e *OpError) Error() string {
-	// todo
-	return ""
+// Wrapf returns an OpError with given status whose description is formatted from given format and
+// arguments. The format supports the %w verb: the wrapped errors become the cause of the OpError,
+// joined by errors.Join if there are several.
+func Wrapf(status opstatus.Status, format string, args ...any) *OpError {
+	formatted := fmt.Errorf(format, args...)
+	var cause error
+	switch wrapper := formatted.(type) {
+	case interface{ Unwrap() error }:
+		cause = wrapper.Unwrap()
+	case interface{ Unwrap() []error }:
+		cause = errors.Join(wrapper.Unwrap()...)
+	}
+	return NewWithStatusAndCause(*status.WithDescription(formatted.Error()), cause)
 }
 
 // StatusFromErrChain finds the first OpError from the causal chain of given error.
 // If one is found, return its status. Otherwise, return nil
 func StatusFromErrChain(err error) *opstatus.Status {
+	if opErr, found := As(err); found {
+		return opErr.Status()
+	}
+	return nil
+}
+
+// As finds the first error in given error chain that is an *OpError, and if one is found, returns
+// it and true. Otherwise, it returns nil and false. Errors joined by errors.Join are inspected too.
+func As(err error) (*OpError, bool) {
 	if IsNil(err) {
-		return nil
+		return nil, false
 	}
-	cause := err
-	for !IsNil(cause) {
-		if match, opErr := AsOpError(cause); match {
-			return opErr.Status()
-		}
-		cause = errors.Unwrap(cause)
+	var opErr *OpError
+	if errors.As(err, &opErr) && opErr != nil {
+		return opErr, true
 	}
-	return nil
+	return nil, false
 }
 
 // AsOpError finds the first error in given error chain that is of type opError,
 // and if one is found, sets target to that error value and returns true. Otherwise,
 // it returns false.
+//
+// Deprecated: use As, which returns the results in the conventional order.
 func AsOpError(err error) (bool, *OpError) {
-	var opErr OpError
-	return errors.As(err, &opErr), &opErr
+	opErr, found := As(err)
+	return found, opErr
 }
 
 // IsNil tells if given err is nil. If the value of given interface variable is nil
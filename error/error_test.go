@@ -0,0 +1,22 @@
+package error
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ikonglong/op-status"
+)
+
+func TestNewWithStatusAndCauseKeepsTheCauseIfNil(t *testing.T) {
+	status := opstatus.StatusUnavailable.WithCause(io.ErrUnexpectedEOF)
+	if err := NewWithStatusAndCause(*status, nil); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("NewWithStatusAndCause(status, nil) lost the cause of the status: %v", err.Cause())
+	}
+	if err := Wrap(*status, io.EOF); !errors.Is(err, io.EOF) {
+		t.Errorf("Wrap() = %v, want caused by io.EOF", err.Cause())
+	}
+	if err := Wrapf(*status, "read %s", "order"); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Wrapf() without %%w lost the cause of the status: %v", err.Cause())
+	}
+}
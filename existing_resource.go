@@ -0,0 +1,29 @@
+package opstatus
+
+// DetailKeyExistingResource is the detail key of the ExistingResource attached by
+// NewResourceAlreadyExists.
+const DetailKeyExistingResource = ReservedDetailKeyPrefix + "existing_resource"
+
+// ExistingResource refers to the resource that made a create operation fail with AlreadyExists.
+type ExistingResource struct {
+	Resource ResourceInfo `json:"resource"`
+	// Location is the URI of the existing resource. HTTP renderers should expose it as the Location
+	// header.
+	Location string `json:"location,omitempty"`
+	// MatchesRequest tells if the existing resource is semantically the one the request would have
+	// created, so that a client doing an idempotent create can treat the failure as a success.
+	MatchesRequest bool `json:"matches_request"`
+}
+
+// NewResourceAlreadyExists returns an AlreadyExists status referring to the existing resource.
+func NewResourceAlreadyExists(existing ExistingResource) *Status {
+	status := StatusAlreadyExists.WithDescriptionf("%s already exists", existing.Resource)
+	status.setDetail(DetailKeyExistingResource, existing)
+	return status
+}
+
+// ExistingResource returns the ExistingResource attached to this status, if any.
+func (s *Status) ExistingResource() (ExistingResource, bool) {
+	existing, found := s.details[DetailKeyExistingResource].(ExistingResource)
+	return existing, found
+}
@@ -0,0 +1,142 @@
+package opstatus
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Explanation is a structured, human-oriented explanation of a Status.
+type Explanation struct {
+	Code          Code
+	Meaning       string
+	RetryAdvice   RetryAdvice
+	TypicalCauses []string
+	Description   string
+	// Owner is the owner of the case of the status, if the case is registered with one.
+	Owner CaseOwner
+	// DocsURL links to the documentation of the case of the status, if its registry document gives
+	// one.
+	DocsURL string
+}
+
+// String renders this explanation as indented text, e.g., for a CLI or the verbose error responses
+// of internal tooling. The fields that are empty are left out.
+func (e Explanation) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v: %s\n", e.Code, e.Meaning)
+	if e.Description != "" {
+		fmt.Fprintf(&b, "  description: %s\n", e.Description)
+	}
+	fmt.Fprintf(&b, "  retry advice: %v\n", e.RetryAdvice)
+	if len(e.TypicalCauses) > 0 {
+		fmt.Fprintf(&b, "  typical causes: %s\n", strings.Join(e.TypicalCauses, "; "))
+	}
+	if e.Owner != (CaseOwner{}) {
+		fmt.Fprintf(&b, "  owner: %s", e.Owner.Team)
+		if e.Owner.Contact != "" {
+			fmt.Fprintf(&b, " (%s)", e.Owner.Contact)
+		}
+		b.WriteString("\n")
+		if e.Owner.RunbookURL != "" {
+			fmt.Fprintf(&b, "  runbook: %s\n", e.Owner.RunbookURL)
+		}
+	}
+	if e.DocsURL != "" {
+		fmt.Fprintf(&b, "  docs: %s\n", e.DocsURL)
+	}
+	return b.String()
+}
+
+type codeGuidance struct {
+	meaning       string
+	typicalCauses []string
+}
+
+var codeToGuidance = map[Code]codeGuidance{
+	CodeOK: {
+		meaning: "The operation completed successfully.",
+	},
+	CodeCancelled: {
+		meaning:       "The operation was cancelled, typically by the caller.",
+		typicalCauses: []string{"the client closed the connection", "the caller cancelled the context"},
+	},
+	CodeUnknown: {
+		meaning:       "An error occurred that could not be classified.",
+		typicalCauses: []string{"an error from another error space was received", "an API returned too little error information"},
+	},
+	CodeInvalidArgument: {
+		meaning:       "The client specified an argument that is invalid regardless of the state of the system.",
+		typicalCauses: []string{"a malformed field value", "a missing required field"},
+	},
+	CodeDeadlineExceeded: {
+		meaning:       "The deadline expired before the operation could complete.",
+		typicalCauses: []string{"a slow downstream dependency", "a deadline too short for the operation"},
+	},
+	CodeNotFound: {
+		meaning:       "Some requested entity was not found.",
+		typicalCauses: []string{"a wrong identifier", "the entity was deleted", "the feature is not rolled out to the caller"},
+	},
+	CodeAlreadyExists: {
+		meaning:       "The entity that the client attempted to create already exists.",
+		typicalCauses: []string{"a duplicate create request", "an identifier collision"},
+	},
+	CodePermissionDenied: {
+		meaning:       "The caller does not have permission to execute the operation.",
+		typicalCauses: []string{"a missing role or permission", "an access control policy denies the caller"},
+	},
+	CodeUnauthenticated: {
+		meaning:       "The request does not have valid authentication credentials.",
+		typicalCauses: []string{"a missing or expired token", "an invalid signature"},
+	},
+	CodeResourceExhausted: {
+		meaning:       "Some resource has been exhausted.",
+		typicalCauses: []string{"a per-user quota or rate limit was exceeded", "the system ran out of space"},
+	},
+	CodeFailedPrecondition: {
+		meaning:       "The system is not in a state required for the operation's execution.",
+		typicalCauses: []string{"the entity is in the wrong state", "a prerequisite step was not performed"},
+	},
+	CodeAborted: {
+		meaning:       "The operation was aborted, typically due to a concurrency issue.",
+		typicalCauses: []string{"a transaction abort", "a failed test-and-set"},
+	},
+	CodeOutOfRange: {
+		meaning:       "The operation was attempted past the valid range.",
+		typicalCauses: []string{"seeking or reading past the end", "a page token beyond the last page"},
+	},
+	CodeUnimplemented: {
+		meaning:       "The operation is not implemented or not enabled in this service.",
+		typicalCauses: []string{"a client newer than the server", "a disabled feature"},
+	},
+	CodeInternal: {
+		meaning:       "Some invariants expected by the underlying system have been broken.",
+		typicalCauses: []string{"a bug", "a corrupted internal state"},
+	},
+	CodeUnavailable: {
+		meaning:       "The service is currently unavailable; this is most likely transient.",
+		typicalCauses: []string{"an overloaded or restarting instance", "a network partition"},
+	},
+	CodeDataLoss: {
+		meaning:       "Unrecoverable data loss or corruption occurred.",
+		typicalCauses: []string{"a checksum mismatch", "a lost storage replica"},
+	},
+}
+
+// Explain returns a structured explanation of this status: what its code means, the retry guidance,
+// the typical causes of such a status, and the owner and documentation link of its case.
+func (s *Status) Explain() Explanation {
+	guidance := codeToGuidance[s.code]
+	var registered RegisteredCase
+	if s.theCase != nil {
+		registered, _ = LookupCase(s.theCase.Identifier())
+	}
+	return Explanation{
+		Code:          s.code,
+		Meaning:       guidance.meaning,
+		RetryAdvice:   s.RetryAdvice(),
+		TypicalCauses: guidance.typicalCauses,
+		Description:   s.description,
+		Owner:         registered.owner,
+		DocsURL:       registered.docsURL,
+	}
+}
@@ -0,0 +1,37 @@
+package opstatus
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestExplainLinksTheDocsOfTheRegisteredCase(t *testing.T) {
+	t.Cleanup(func() {
+		ReloadRegistry(fstest.MapFS{"registry.json": {Data: []byte(`{}`)}}, "registry.json")
+	})
+	fsys := fstest.MapFS{"registry.json": {Data: []byte(`{"cases": [{
+		"identifier": "explain_test_case", "code": "NotFound", "description": "order not found",
+		"owner": {"team": "orders", "contact": "#orders"},
+		"docs_url": "https://docs.example.com/errors/explain_test_case"
+	}]}`)}}
+	if problems := LoadRegistry(fsys, "registry.json"); !problems.IsOK() {
+		t.Fatal(problems.Statuses())
+	}
+	registered, _ := LookupCase("explain_test_case")
+
+	explanation := registered.Status().Explain()
+	if explanation.DocsURL != "https://docs.example.com/errors/explain_test_case" {
+		t.Errorf("DocsURL = %q", explanation.DocsURL)
+	}
+	rendered := explanation.String()
+	for _, want := range []string{"order not found", "owner: orders (#orders)", "docs: https://docs.example.com/errors/explain_test_case"} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("String() = %q, want it to contain %q", rendered, want)
+		}
+	}
+
+	if explanation := NewWithCode(CodeNotFound).Explain(); explanation.DocsURL != "" || strings.Contains(explanation.String(), "docs:") {
+		t.Errorf("explanation of a status without case = %+v", explanation)
+	}
+}
@@ -0,0 +1,131 @@
+package opstatus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DetailKeyFieldViolations is the detail key of the field violations attached by NewBadRequest.
+const DetailKeyFieldViolations = ReservedDetailKeyPrefix + "field_violations"
+
+// FieldViolation describes a single invalid field of a request.
+type FieldViolation struct {
+	// Field locates the field: a JSON Pointer (RFC 6901), e.g., "/items/0/sku", or a protobuf field
+	// path, e.g., "items[0].sku".
+	Field       string `json:"field"`
+	Description string `json:"description"`
+	// Value is the offending value. Leave it unset, or use WithRedactedValue, for sensitive fields.
+	Value any `json:"value,omitempty"`
+	// Redacted tells that the offending value was withheld.
+	Redacted bool `json:"redacted,omitempty"`
+	// Offset is the byte offset of the violation in the request body, if known.
+	Offset int64 `json:"offset,omitempty"`
+	// ExpectedType is the type the field should have, for type mismatches.
+	ExpectedType string `json:"expected_type,omitempty"`
+}
+
+// WithRedactedValue returns a copy of this violation without its offending value.
+func (v FieldViolation) WithRedactedValue() FieldViolation {
+	v.Value = nil
+	v.Redacted = true
+	return v
+}
+
+// NewBadRequest returns an InvalidArgument status carrying given field violations.
+func NewBadRequest(violations ...FieldViolation) *Status {
+	desc := "the request is invalid"
+	if len(violations) == 1 {
+		desc = fmt.Sprintf("invalid field %s: %s", violations[0].Field, violations[0].Description)
+	} else if len(violations) > 1 {
+		desc = fmt.Sprintf("the request has %d invalid fields", len(violations))
+	}
+	status := StatusInvalidArgument.WithDescription(desc)
+	status.setDetail(DetailKeyFieldViolations, violations)
+	return status
+}
+
+// FieldViolations returns the field violations attached to this status, if any.
+func (s *Status) FieldViolations() []FieldViolation {
+	violations, _ := s.details[DetailKeyFieldViolations].([]FieldViolation)
+	return violations
+}
+
+// JSONPointer builds a JSON Pointer (RFC 6901) from given reference tokens, e.g., "items", "0" and
+// "sku" yield "/items/0/sku".
+func JSONPointer(tokens ...string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(token))
+	}
+	return b.String()
+}
+
+// FieldViolationFromJSONError builds a field violation from an error returned by encoding/json
+// when decoding a request body: a type mismatch, a syntax error or an unknown field rejected by
+// Decoder.DisallowUnknownFields. The decoder, if given, provides the offset of unknown fields. It
+// returns false for other errors.
+func FieldViolationFromJSONError(err error, dec *json.Decoder) (FieldViolation, bool) {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		var tokens []string
+		if typeErr.Field != "" {
+			tokens = strings.Split(typeErr.Field, ".")
+		}
+		return FieldViolation{
+			Field:        JSONPointer(tokens...),
+			Description:  fmt.Sprintf("expected %v, got %s", typeErr.Type, typeErr.Value),
+			Offset:       typeErr.Offset,
+			ExpectedType: typeErr.Type.String(),
+		}, true
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return FieldViolation{
+			Description: syntaxErr.Error(),
+			Offset:      syntaxErr.Offset,
+		}, true
+	}
+	const unknownFieldPrefix = "json: unknown field "
+	if msg := err.Error(); strings.HasPrefix(msg, unknownFieldPrefix) {
+		name := strings.Trim(strings.TrimPrefix(msg, unknownFieldPrefix), `"`)
+		violation := FieldViolation{
+			Field:       JSONPointer(name),
+			Description: "unknown field",
+		}
+		if dec != nil {
+			violation.Offset = dec.InputOffset()
+		}
+		return violation, true
+	}
+	return FieldViolation{}, false
+}
+
+// FromJSONDecodeError translates an error returned by encoding/json when decoding a request body
+// into a status, instead of exposing raw Go error text: type mismatches, syntax errors and unknown
+// fields yield InvalidArgument with a field violation telling the field path, offset and expected
+// type, an empty or truncated body yields InvalidArgument, and misuses of encoding/json yield
+// Internal. The decoder, if given, provides the offset of unknown fields. Given error is kept as the
+// cause. A nil error yields nil.
+func FromJSONDecodeError(err error, dec *json.Decoder) *Status {
+	if err == nil {
+		return nil
+	}
+	if violation, ok := FieldViolationFromJSONError(err, dec); ok {
+		return NewBadRequest(violation).WithCause(err)
+	}
+	var invalidErr *json.InvalidUnmarshalError
+	switch {
+	case errors.Is(err, io.EOF):
+		return StatusInvalidArgument.WithDescription("the request body is empty").WithCause(err)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return StatusInvalidArgument.WithDescription("the request body is truncated").WithCause(err)
+	case errors.As(err, &invalidErr):
+		return StatusInternal.WithDescription(err.Error()).WithCause(err)
+	default:
+		return StatusInvalidArgument.WithDescription("the request body is malformed").WithCause(err)
+	}
+}
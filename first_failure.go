@@ -0,0 +1,129 @@
+package opstatus
+
+import (
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxFirstFailures bounds the memory used by the first-failure capture.
+const maxFirstFailures = 1024
+
+// FailureSnapshot is the rich snapshot captured for the first occurrence of a failure.
+type FailureSnapshot struct {
+	// Fingerprint identifies the failure: the short reference of its status and the call site
+	// creating its OpError, e.g., OPS-IN-1A2B@/src/orders/service.go:42.
+	Fingerprint string    `json:"fingerprint"`
+	CapturedAt  time.Time `json:"captured_at"`
+	Status      *Status   `json:"status"`
+	// Cause is the message of the cause of the status, if any.
+	Cause string `json:"cause,omitempty"`
+	Stack string `json:"stack"`
+}
+
+var (
+	firstFailuresMu sync.Mutex
+	firstFailures   = map[string]FailureSnapshot{}
+)
+
+// CaptureFirstFailures turns on the capture of a snapshot, stack included, for the first OpError
+// of each fingerprint in this process, to debug flaky operations. Since the fingerprints include
+// the call site, statuses without case are told apart by where they are raised. The next OpErrors
+// with a known fingerprint only cost the lookup of their call site and of the fingerprint. At most 1024 fingerprints are captured. It returns the
+// function turning the capture off; the snapshots are kept.
+func CaptureFirstFailures() (stop func()) {
+	return Observe(captureFirstFailure)
+}
+
+func captureFirstFailure(s *Status) {
+	fingerprint := s.ShortRef().String() + "@" + callSite()
+	firstFailuresMu.Lock()
+	defer firstFailuresMu.Unlock()
+	if _, found := firstFailures[fingerprint]; found || len(firstFailures) >= maxFirstFailures {
+		return
+	}
+	snapshot := FailureSnapshot{
+		Fingerprint: fingerprint,
+		CapturedAt:  now(),
+		Status:      s.derive(),
+		Stack:       string(debug.Stack()),
+	}
+	if s.cause != nil {
+		snapshot.Cause = s.cause.Error()
+	}
+	firstFailures[fingerprint] = snapshot
+}
+
+// modulePackages are the packages of this module whose frames are skipped to find the call site
+// creating an OpError.
+var modulePackages = map[string]bool{
+	"github.com/ikonglong/op-status":       true,
+	"github.com/ikonglong/op-status/error": true,
+}
+
+// callSite returns the file and line of the first caller outside of this module creating an
+// OpError, its tests included.
+func callSite() string {
+	var pcs [32]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+	for {
+		frame, more := frames.Next()
+		if !modulePackages[funcPackage(frame.Function)] || strings.HasSuffix(frame.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// funcPackage returns the package path of given function name, e.g., net/http for
+// net/http.(*Client).Do.
+func funcPackage(function string) string {
+	slash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[slash+1:], "."); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+	return function
+}
+
+// FirstFailures returns the captured snapshots, oldest first.
+func FirstFailures() []FailureSnapshot {
+	firstFailuresMu.Lock()
+	defer firstFailuresMu.Unlock()
+	snapshots := make([]FailureSnapshot, 0, len(firstFailures))
+	for _, snapshot := range firstFailures {
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CapturedAt.Before(snapshots[j].CapturedAt)
+	})
+	return snapshots
+}
+
+// ResetFirstFailures drops the captured snapshots, so that the next occurrences are captured again.
+func ResetFirstFailures() {
+	firstFailuresMu.Lock()
+	defer firstFailuresMu.Unlock()
+	firstFailures = map[string]FailureSnapshot{}
+}
+
+// FirstFailuresHandler returns a debug endpoint serving the captured snapshots as JSON. It exposes
+// stacks and details unredacted, so it must not be reachable by clients.
+func FirstFailuresHandler() nethttp.Handler {
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		body, err := json.MarshalIndent(FirstFailures(), "", "  ")
+		if err != nil {
+			nethttp.Error(w, err.Error(), nethttp.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(append(body, '\n'))
+	})
+}
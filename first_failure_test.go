@@ -0,0 +1,56 @@
+package opstatus
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFirstFailuresAreToldApartByCallSite(t *testing.T) {
+	ResetFirstFailures()
+	stop := CaptureFirstFailures()
+	t.Cleanup(func() {
+		stop()
+		ResetFirstFailures()
+	})
+
+	for i := 0; i < 3; i++ {
+		_ = StatusInternal.WithDescription("disk failed").Err()
+	}
+	_ = StatusInternal.WithDescription("cache failed").Err()
+
+	snapshots := FirstFailures()
+	if len(snapshots) != 2 {
+		t.Fatalf("FirstFailures() = %d snapshots, want 2", len(snapshots))
+	}
+	for _, snapshot := range snapshots {
+		if !strings.Contains(snapshot.Fingerprint, "first_failure_test.go:") {
+			t.Errorf("Fingerprint = %q, want the call site of the test", snapshot.Fingerprint)
+		}
+	}
+	if snapshots[0].Status.Description() != "disk failed" || snapshots[1].Status.Description() != "cache failed" {
+		t.Errorf("captured %q and %q", snapshots[0].Status.Description(), snapshots[1].Status.Description())
+	}
+}
+
+func TestFirstFailuresHandler(t *testing.T) {
+	ResetFirstFailures()
+	stop := CaptureFirstFailures()
+	t.Cleanup(func() {
+		stop()
+		ResetFirstFailures()
+	})
+	_ = StatusUnavailable.WithDescription("down").Err()
+
+	recorder := httptest.NewRecorder()
+	FirstFailuresHandler().ServeHTTP(recorder, httptest.NewRequest(nethttp.MethodGet, "/debug/first-failures", nil))
+	var snapshots []FailureSnapshot
+	if err := json.Unmarshal(recorder.Body.Bytes(), &snapshots); err != nil {
+		t.Fatal(err)
+	}
+	if recorder.Code != nethttp.StatusOK || len(snapshots) != 1 || snapshots[0].Status.Code() != CodeUnavailable {
+		t.Errorf("handler served %d %s", recorder.Code, recorder.Body)
+	}
+}
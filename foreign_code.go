@@ -0,0 +1,35 @@
+package opstatus
+
+import "fmt"
+
+// DetailKeyForeignCode is the detail key of the ForeignCode recorded by NewWithForeignCode.
+const DetailKeyForeignCode = ReservedDetailKeyPrefix + "foreign_code"
+
+// ForeignCode is a code received from a peer, typically a newer one, that isn't known locally.
+type ForeignCode struct {
+	Value int    `json:"value"`
+	Name  string `json:"name,omitempty"`
+}
+
+// NewWithForeignCode returns an Unknown status for a code received from a peer that isn't known
+// locally. The original value and name are preserved as a detail instead of being silently
+// collapsed, so that they can be logged or relayed. The name may be empty if it wasn't received.
+func NewWithForeignCode(value int, name string) *Status {
+	desc := fmt.Sprintf("Unknown op status code: %v", value)
+	if name != "" {
+		desc = fmt.Sprintf("Unknown op status code: %s(%v)", name, value)
+	}
+	status := StatusUnknown.WithDescription(desc)
+	status.setDetail(DetailKeyForeignCode, ForeignCode{
+		Value: value,
+		Name:  name,
+	})
+	return status
+}
+
+// ForeignCode returns the foreign code this status was decoded from, if its code wasn't known
+// locally.
+func (s *Status) ForeignCode() (ForeignCode, bool) {
+	code, found := s.details[DetailKeyForeignCode].(ForeignCode)
+	return code, found
+}
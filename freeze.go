@@ -0,0 +1,55 @@
+package opstatus
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrFrozen is returned by the functions changing the package-level configuration once Freeze has
+// been called.
+var ErrFrozen = errors.New("opstatus: configuration is frozen")
+
+var (
+	frozen          atomic.Bool
+	panicOnMutation atomic.Bool
+)
+
+// FreezeOption configures Freeze.
+type FreezeOption func()
+
+// PanicOnMutation makes the mutation attempts after Freeze panic instead of returning ErrFrozen.
+// It is meant for tests, to surface late registrations loudly.
+func PanicOnMutation() FreezeOption {
+	return func() {
+		panicOnMutation.Store(true)
+	}
+}
+
+// Freeze locks the package-level configuration: every function documented to fail with ErrFrozen,
+// e.g., the ones registering cases, case codecs, case owners, alert policies and panic mappings,
+// or changing the HTTP mapping, the clock or the source of randomness, fails from then on. It is
+// meant to be called once initialization is over, so that no registration can race with request
+// processing. This includes registry reloads.
+func Freeze(opts ...FreezeOption) {
+	for _, opt := range opts {
+		opt()
+	}
+	frozen.Store(true)
+}
+
+// Frozen tells if Freeze has been called.
+func Frozen() bool {
+	return frozen.Load()
+}
+
+// checkNotFrozen returns ErrFrozen, or panics with it if so configured, if the configuration is
+// frozen.
+func checkNotFrozen() error {
+	if !frozen.Load() {
+		return nil
+	}
+	if panicOnMutation.Load() {
+		panic(ErrFrozen)
+	}
+	return ErrFrozen
+}
@@ -0,0 +1,82 @@
+// Package fsstatus translates filesystem errors, e.g., *fs.PathError and syscall errnos, into
+// precise operation statuses instead of a blanket Internal.
+package fsstatus
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"syscall"
+
+	"github.com/ikonglong/op-status"
+)
+
+// Detail keys of the filesystem metadata recorded by FromError.
+const (
+	DetailKeyOp   = "fs_op"
+	DetailKeyPath = "fs_path"
+)
+
+type errnoMapping struct {
+	errno syscall.Errno
+	code  opstatus.Code
+}
+
+// errnoMappings is checked in order, before the generic fs errors.
+var errnoMappings = []errnoMapping{
+	{syscall.ENOSPC, opstatus.CodeResourceExhausted},
+	{syscall.EDQUOT, opstatus.CodeResourceExhausted},
+	{syscall.EMFILE, opstatus.CodeResourceExhausted},
+	{syscall.ENFILE, opstatus.CodeResourceExhausted},
+	{syscall.EIO, opstatus.CodeDataLoss},
+	{syscall.EROFS, opstatus.CodeFailedPrecondition},
+	{syscall.ENOTEMPTY, opstatus.CodeFailedPrecondition},
+	{syscall.ENOTDIR, opstatus.CodeFailedPrecondition},
+	{syscall.EISDIR, opstatus.CodeFailedPrecondition},
+	{syscall.ENAMETOOLONG, opstatus.CodeInvalidArgument},
+	{syscall.EINVAL, opstatus.CodeInvalidArgument},
+	{syscall.EBUSY, opstatus.CodeUnavailable},
+	{syscall.EAGAIN, opstatus.CodeUnavailable},
+	{syscall.ETIMEDOUT, opstatus.CodeDeadlineExceeded},
+}
+
+// FromError returns the status for given filesystem error. ENOENT yields NotFound, EEXIST
+// AlreadyExists, EACCES and EPERM PermissionDenied, ENOSPC ResourceExhausted and EIO DataLoss;
+// see the source for the complete mapping. Unrecognized errors yield Unknown. The operation and
+// path of a *fs.PathError are recorded as details, and given error as the cause. A nil error yields
+// nil.
+func FromError(err error) *opstatus.Status {
+	if err == nil {
+		return nil
+	}
+	status := opstatus.NewWithCode(codeOf(err)).WithDescription(err.Error()).WithCause(err)
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		status = status.WithDetails(DetailKeyOp, pathErr.Op, DetailKeyPath, pathErr.Path)
+	}
+	return status
+}
+
+func codeOf(err error) opstatus.Code {
+	for _, mapping := range errnoMappings {
+		if errors.Is(err, mapping.errno) {
+			return mapping.code
+		}
+	}
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return opstatus.CodeNotFound
+	case errors.Is(err, fs.ErrExist):
+		return opstatus.CodeAlreadyExists
+	case errors.Is(err, fs.ErrPermission):
+		return opstatus.CodePermissionDenied
+	case errors.Is(err, fs.ErrInvalid):
+		return opstatus.CodeInvalidArgument
+	case errors.Is(err, fs.ErrClosed):
+		return opstatus.CodeFailedPrecondition
+	case errors.Is(err, os.ErrDeadlineExceeded):
+		return opstatus.CodeDeadlineExceeded
+	default:
+		return opstatus.CodeUnknown
+	}
+}
@@ -0,0 +1,70 @@
+package opstatus
+
+import (
+	"sync"
+	"time"
+)
+
+// HistoryEntry is a non-OK status recorded by a History.
+type HistoryEntry struct {
+	Time      time.Time
+	Operation string
+	Status    *Status
+	Ref       ShortRef
+}
+
+// History is a bounded, in-memory ring of the last non-OK statuses of a process, so that the recent
+// failures of an instance can be inspected without access to its logs. It is safe for concurrent
+// use.
+type History struct {
+	mu      sync.Mutex
+	entries []HistoryEntry
+	next    int
+	full    bool
+}
+
+// NewHistory returns a History keeping the given number of last entries.
+func NewHistory(size int) *History {
+	if size <= 0 {
+		size = 1
+	}
+	return &History{entries: make([]HistoryEntry, size)}
+}
+
+// Record records a copy of given status of given operation, so that later changes to the status
+// don't rewrite the history, evicting the oldest entry if the history is full. OK statuses are
+// ignored.
+func (h *History) Record(operation string, status *Status) {
+	if status == nil || status.IsOK() {
+		return
+	}
+	entry := HistoryEntry{
+		Time:      now(),
+		Operation: operation,
+		Status:    status.Clone(),
+		Ref:       status.ShortRef(),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % len(h.entries)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// Entries returns the recorded entries, oldest first. Their statuses are copies.
+func (h *History) Entries() []HistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var entries []HistoryEntry
+	if h.full {
+		entries = append(entries, h.entries[h.next:]...)
+	}
+	entries = append(entries, h.entries[:h.next]...)
+	for i := range entries {
+		entries[i].Status = entries[i].Status.Clone()
+	}
+	return entries
+}
@@ -0,0 +1,30 @@
+package opstatus
+
+import "testing"
+
+func TestHistoryKeepsCopies(t *testing.T) {
+	history := NewHistory(2)
+	status := StatusUnavailable.WithDescription("down")
+	history.Record("sync", status)
+	status.AddDetail("later", true)
+	history.Entries()[0].Status.AddDetail("reader", true)
+
+	entries := history.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Entries() = %d entries, want 1", len(entries))
+	}
+	if details := entries[0].Status.Details(); len(details) != 0 {
+		t.Errorf("recorded status details = %v, want none", details)
+	}
+}
+
+func TestHistoryEvictsTheOldestEntry(t *testing.T) {
+	history := NewHistory(2)
+	for _, operation := range []string{"a", "b", "c"} {
+		history.Record(operation, StatusUnavailable.WithDescription(operation))
+	}
+	entries := history.Entries()
+	if len(entries) != 2 || entries[0].Operation != "b" || entries[1].Operation != "c" {
+		t.Errorf("Entries() = %+v, want b and c", entries)
+	}
+}
@@ -11,31 +11,41 @@ const (
 	StatusOK         = Status(200)
 	StatusBadRequest = Status(400)
 
-	StatusUnauthorized        = Status(401)
-	StatusForbidden           = Status(403)
-	StatusNotFound            = Status(404)
-	StatusConflict            = Status(409)
-	StatusTooManyRequests     = Status(429)
-	StatusClientClosedRequest = Status(499)
-	StatusInternalServerError = Status(500)
-	StatusNotImplemented      = Status(501)
-	StatusServiceUnavailable  = Status(503)
-	StatusTimeout             = Status(504)
+	StatusUnauthorized         = Status(401)
+	StatusForbidden            = Status(403)
+	StatusNotFound             = Status(404)
+	StatusConflict             = Status(409)
+	StatusGone                 = Status(410)
+	StatusPreconditionFailed   = Status(412)
+	StatusPayloadTooLarge      = Status(413)
+	StatusUnsupportedMediaType = Status(415)
+	StatusUnprocessableEntity  = Status(422)
+	StatusTooManyRequests      = Status(429)
+	StatusClientClosedRequest  = Status(499)
+	StatusInternalServerError  = Status(500)
+	StatusNotImplemented       = Status(501)
+	StatusServiceUnavailable   = Status(503)
+	StatusTimeout              = Status(504)
 )
 
 var statusToName = map[Status]statusName{
-	StatusOK:                  "OK",
-	StatusBadRequest:          "BadRequest",
-	StatusUnauthorized:        "Unauthorized",
-	StatusForbidden:           "Forbidden",
-	StatusNotFound:            "NotFound",
-	StatusConflict:            "Conflict",
-	StatusTooManyRequests:     "TooManyRequests",
-	StatusClientClosedRequest: "ClientClosedRequest",
-	StatusInternalServerError: "InternalServerError",
-	StatusNotImplemented:      "NotImplemented",
-	StatusServiceUnavailable:  "ServiceUnavailable",
-	StatusTimeout:             "Timeout",
+	StatusOK:                   "OK",
+	StatusBadRequest:           "BadRequest",
+	StatusUnauthorized:         "Unauthorized",
+	StatusForbidden:            "Forbidden",
+	StatusNotFound:             "NotFound",
+	StatusConflict:             "Conflict",
+	StatusGone:                 "Gone",
+	StatusPreconditionFailed:   "PreconditionFailed",
+	StatusPayloadTooLarge:      "PayloadTooLarge",
+	StatusUnsupportedMediaType: "UnsupportedMediaType",
+	StatusUnprocessableEntity:  "UnprocessableEntity",
+	StatusTooManyRequests:      "TooManyRequests",
+	StatusClientClosedRequest:  "ClientClosedRequest",
+	StatusInternalServerError:  "InternalServerError",
+	StatusNotImplemented:       "NotImplemented",
+	StatusServiceUnavailable:   "ServiceUnavailable",
+	StatusTimeout:              "Timeout",
 }
 
 func (hs *Status) Code() int {
@@ -0,0 +1,87 @@
+package opstatus
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ikonglong/op-status/http"
+)
+
+var (
+	// httpMappingMu serializes the updates of httpMapping, which are copy-on-write so that readers
+	// never lock. It also guards the layers httpMapping is built from.
+	httpMappingMu sync.Mutex
+	httpMapping   atomic.Pointer[map[Code]http.Status]
+
+	// The layers of httpMapping, from the lowest precedence to the highest, over the default
+	// mapping: the active profile, the overrides made by code and the ones of registry documents.
+	httpProfile           MappingProfile
+	httpOverrides         = map[Code]http.Status{}
+	registryHTTPOverrides = map[Code]http.Status{}
+)
+
+func init() {
+	mapping := copyHTTPMapping(codeToHTTPStatus)
+	httpMapping.Store(&mapping)
+}
+
+func currentHTTPMapping() map[Code]http.Status {
+	return *httpMapping.Load()
+}
+
+// updateHTTPMapping updates the layers of the mapping with given function, then atomically
+// replaces the current mapping by the one rebuilt from them.
+func updateHTTPMapping(update func()) {
+	httpMappingMu.Lock()
+	defer httpMappingMu.Unlock()
+	update()
+	mapping := copyHTTPMapping(codeToHTTPStatus)
+	for _, layer := range []map[Code]http.Status{httpProfile.HTTPStatuses, httpOverrides, registryHTTPOverrides} {
+		for code, status := range layer {
+			mapping[code] = status
+		}
+	}
+	httpMapping.Store(&mapping)
+}
+
+func copyHTTPMapping(mapping map[Code]http.Status) map[Code]http.Status {
+	copied := make(map[Code]http.Status, len(mapping))
+	for code, status := range mapping {
+		copied[code] = status
+	}
+	return copied
+}
+
+// MapToHTTPStatus overrides the HTTP status given code is mapped to, on top of the active mapping
+// profile. The HTTP status must be one defined by the http package. The change is atomic and safe
+// while statuses are being converted.
+// It returns ErrFrozen once the configuration is frozen.
+func MapToHTTPStatus(code Code, statusCode int) error {
+	if err := checkNotFrozen(); err != nil {
+		return err
+	}
+	if _, found := codeToHTTPStatus[code]; !found {
+		return fmt.Errorf("unknown op status code %v", code)
+	}
+	if !http.IsDefined(statusCode) {
+		return fmt.Errorf("HTTP status %d is not defined", statusCode)
+	}
+	updateHTTPMapping(func() {
+		httpOverrides[code] = http.Status(statusCode)
+	})
+	return nil
+}
+
+// UseUnprocessableEntity splits the semantic errors from the malformed requests: InvalidArgument
+// stays mapped to 400 Bad Request while FailedPrecondition is mapped to 422 Unprocessable Entity.
+// It returns ErrFrozen once the configuration is frozen.
+func UseUnprocessableEntity() error {
+	if err := checkNotFrozen(); err != nil {
+		return err
+	}
+	updateHTTPMapping(func() {
+		httpOverrides[CodeFailedPrecondition] = http.StatusUnprocessableEntity
+	})
+	return nil
+}
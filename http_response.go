@@ -0,0 +1,67 @@
+package opstatus
+
+import (
+	"errors"
+	nethttp "net/http"
+	"net/url"
+	"strings"
+)
+
+// DetailKeyLocation is the detail key of the Location header of an unexpected redirect.
+const DetailKeyLocation = ReservedDetailKeyPrefix + "location"
+
+// NewByHTTPResponse returns the status of an HTTP response received by a client, given its status
+// code and headers. Unlike NewByHTTPStatus, it distinguishes the non-error responses that aren't
+// plain successes:
+//
+//   - any 2xx response yields OK
+//   - 304 Not Modified yields OK with notModified set, telling the cached representation is current
+//   - any other 3xx response, i.e., a redirect the client didn't follow, yields FailedPrecondition
+//     with the Location header as a detail
+//
+// Other responses are mapped by NewByHTTPStatus.
+func NewByHTTPResponse(statusCode int, header nethttp.Header) (status *Status, notModified bool) {
+	switch {
+	case statusCode == nethttp.StatusNotModified:
+		return StatusOK.derive(), true
+	case statusCode >= 200 && statusCode < 300:
+		return StatusOK.derive(), false
+	case statusCode >= 300 && statusCode < 400:
+		status = StatusFailedPrecondition.WithDescriptionf("unexpected redirect %d %s", statusCode, nethttp.StatusText(statusCode))
+		if location := header.Get("Location"); location != "" {
+			status.setDetail(DetailKeyLocation, location)
+		}
+		return status, false
+	default:
+		return NewByHTTPStatus(statusCode), false
+	}
+}
+
+// FromHTTPClientError returns the status for an error returned by net/http's Client, e.g., for a
+// redirect loop: the Client gives up after 10 redirects, which yields FailedPrecondition. Timeouts
+// yield DeadlineExceeded, and other errors Unavailable. Given error is kept as the cause. A nil
+// error yields nil.
+func FromHTTPClientError(err error) *Status {
+	if err == nil {
+		return nil
+	}
+	type timeout interface{ Timeout() bool }
+	if t, ok := err.(timeout); ok && t.Timeout() {
+		return StatusDeadlineExceeded.WithDescription(err.Error()).WithCause(err)
+	}
+	if isRedirectLoop(err) {
+		return StatusFailedPrecondition.WithDescription(err.Error()).WithCause(err)
+	}
+	return StatusUnavailable.WithDescription(err.Error()).WithCause(err)
+}
+
+// isRedirectLoop tells if given error is the one net/http's default redirect policy returns when a
+// client follows too many redirects.
+func isRedirectLoop(err error) bool {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return false
+	}
+	msg := urlErr.Err.Error()
+	return strings.HasPrefix(msg, "stopped after ") && strings.HasSuffix(msg, " redirects")
+}
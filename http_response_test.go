@@ -0,0 +1,22 @@
+package opstatus
+
+import (
+	nethttp "net/http"
+	"testing"
+)
+
+func TestNewByHTTPResponseRecordsTheRedirectLocation(t *testing.T) {
+	header := nethttp.Header{}
+	header.Set("Location", "https://example.com/moved")
+	status, notModified := NewByHTTPResponse(nethttp.StatusMovedPermanently, header)
+
+	if notModified || status.Code() != CodeFailedPrecondition {
+		t.Fatalf("NewByHTTPResponse(301) = %v, %v, want FailedPrecondition", status.Code(), notModified)
+	}
+	if location := status.Details()[DetailKeyLocation]; location != "https://example.com/moved" {
+		t.Errorf("details[%s] = %v, want the Location header", DetailKeyLocation, location)
+	}
+	if _, found := status.Details()["location"]; found {
+		t.Errorf("Location header is recorded under a user detail key")
+	}
+}
@@ -0,0 +1,93 @@
+// Package httpcond evaluates HTTP conditional requests (RFC 9110, section 13) and reports the
+// failed ones as operation statuses.
+package httpcond
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ikonglong/op-status"
+)
+
+// Result is the outcome of evaluating the preconditions of a request.
+type Result int
+
+const (
+	// Proceed means the preconditions hold and the request should be performed.
+	Proceed Result = iota
+	// NotModified means the client's representation is current: a GET or HEAD request should be
+	// answered with 304 Not Modified.
+	NotModified
+	// Failed means a precondition failed; the returned status tells which.
+	Failed
+)
+
+// Check evaluates the If-Match, If-Unmodified-Since, If-None-Match and If-Modified-Since headers
+// of given request against the current ETag and modification time of the target resource. An
+// empty etag means the resource doesn't exist; a zero lastModified means it is unknown.
+//
+// A failed If-Match is reported as Aborted with a ConflictInfo, since the client based its change
+// on a stale version. Other failed preconditions are reported as FailedPrecondition.
+func Check(r *http.Request, etag string, lastModified time.Time) (Result, *opstatus.Status) {
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		if !matches(ifMatch, etag, false) {
+			return Failed, opstatus.NewConflict(opstatus.ConflictInfo{
+				ExpectedVersion: ifMatch,
+				ActualVersion:   etag,
+			})
+		}
+	} else if since, ok := parseTime(r.Header.Get("If-Unmodified-Since")); ok && !lastModified.IsZero() {
+		if lastModified.Truncate(time.Second).After(since) {
+			return Failed, opstatus.StatusFailedPrecondition.WithDescriptionf(
+				"the resource was modified at %s, after %s", lastModified.UTC().Format(http.TimeFormat), since.Format(http.TimeFormat))
+		}
+	}
+
+	safe := r.Method == http.MethodGet || r.Method == http.MethodHead
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		if matches(ifNoneMatch, etag, true) {
+			if safe {
+				return NotModified, nil
+			}
+			return Failed, opstatus.StatusFailedPrecondition.WithDescriptionf("the resource matches If-None-Match %s", ifNoneMatch)
+		}
+	} else if since, ok := parseTime(r.Header.Get("If-Modified-Since")); ok && safe && !lastModified.IsZero() {
+		if !lastModified.Truncate(time.Second).After(since) {
+			return NotModified, nil
+		}
+	}
+	return Proceed, nil
+}
+
+// matches tells if given ETag is listed in given If-Match or If-None-Match header value. The weak
+// comparison ignores the W/ prefix, as required for If-None-Match.
+func matches(header, etag string, weak bool) bool {
+	if etag == "" {
+		return false
+	}
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if weak {
+			if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+			continue
+		}
+		if !strings.HasPrefix(candidate, "W/") && !strings.HasPrefix(etag, "W/") && candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func parseTime(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	t, err := http.ParseTime(value)
+	return t, err == nil
+}
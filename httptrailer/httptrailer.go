@@ -0,0 +1,88 @@
+// Package httptrailer reports the status of a streaming HTTP response in its trailers, so that a
+// failure occurring after the headers were sent is not mistaken for a truncated stream. Trailers
+// require HTTP/2 or a chunked HTTP/1.1 response.
+package httptrailer
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/ikonglong/op-status"
+)
+
+// The trailers the status is encoded into. The gRPC ones let gRPC-aware proxies and clients, e.g.,
+// gRPC-Web, interpret the status too.
+const (
+	TrailerCode        = "Op-Status-Code"
+	TrailerCase        = "Op-Status-Case"
+	TrailerDescription = "Op-Status-Description"
+
+	TrailerGRPCStatus  = "Grpc-Status"
+	TrailerGRPCMessage = "Grpc-Message"
+)
+
+// Write encodes given status into the trailers of the response. It can be called at any point of
+// the stream, after the headers were sent included, but the response must not be complete yet.
+func Write(w http.ResponseWriter, status *opstatus.Status) error {
+	code := status.Code().Value()
+	if foreign, found := status.ForeignCode(); found {
+		code = foreign.Value
+	}
+	description := url.PathEscape(status.Description())
+	setTrailer(w, TrailerCode, strconv.Itoa(code))
+	setTrailer(w, TrailerDescription, description)
+	setTrailer(w, TrailerGRPCStatus, strconv.Itoa(code))
+	setTrailer(w, TrailerGRPCMessage, description)
+	if theCase := status.TheCase(); theCase != nil {
+		encoded, err := opstatus.EncodeCase(theCase)
+		if err != nil {
+			return err
+		}
+		setTrailer(w, TrailerCase, string(encoded))
+	}
+	return nil
+}
+
+func setTrailer(w http.ResponseWriter, name, value string) {
+	w.Header().Set(http.TrailerPrefix+name, value)
+}
+
+// Read returns the status encoded into the trailers of given response. The trailers are only
+// available once the body has been read to EOF, so Read drains what is left of it. If the response
+// has no status trailer, e.g., the stream was truncated, it returns false.
+func Read(resp *http.Response) (*opstatus.Status, bool, error) {
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return nil, false, err
+	}
+	value := resp.Trailer.Get(TrailerCode)
+	if value == "" {
+		value = resp.Trailer.Get(TrailerGRPCStatus)
+	}
+	if value == "" {
+		return nil, false, nil
+	}
+	code, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, false, errors.New("malformed status code trailer " + strconv.Quote(value))
+	}
+	status := opstatus.NewWithCodeValue(code)
+
+	description := resp.Trailer.Get(TrailerDescription)
+	if description == "" {
+		description = resp.Trailer.Get(TrailerGRPCMessage)
+	}
+	if unescaped, err := url.PathUnescape(description); err == nil {
+		description = unescaped
+	}
+	if encoded := resp.Trailer.Get(TrailerCase); encoded != "" {
+		theCase, err := opstatus.DecodeCase([]byte(encoded))
+		if err != nil {
+			return nil, false, err
+		}
+		return status.WithCaseAndDesc(theCase, description), true, nil
+	}
+	return status.WithDescription(description), true, nil
+}
@@ -0,0 +1,130 @@
+// Package legacy bridges pre-existing error code schemes, e.g., ERR_1042 style codes, to operation
+// statuses, so that services can migrate incrementally while still answering legacy clients.
+package legacy
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/ikonglong/op-status"
+)
+
+// DetailKeyLegacyCode is the detail key under which FromLegacy records the legacy code.
+const DetailKeyLegacyCode = "legacy_code"
+
+// Bridge maps legacy error codes to status templates and back. It is safe for concurrent use.
+type Bridge struct {
+	mu       sync.RWMutex
+	toStatus map[string]*opstatus.Status
+	// byCase and byCode map case identifiers, and codes for the templates without case, to legacy
+	// codes.
+	byCase map[string]string
+	byCode map[opstatus.Code]string
+}
+
+// NewBridge returns an empty Bridge.
+func NewBridge() *Bridge {
+	return &Bridge{
+		toStatus: map[string]*opstatus.Status{},
+		byCase:   map[string]string{},
+		byCode:   map[opstatus.Code]string{},
+	}
+}
+
+// Map maps given legacy code to given status template. The reverse mapping is set only by the
+// first legacy code mapped to the case, or to the code if the template has no case. The bridge
+// keeps a copy of the template, so that later changes to it don't affect the mapping.
+func (b *Bridge) Map(legacyCode string, template *opstatus.Status) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.toStatus[legacyCode] = template.Clone()
+	if theCase := template.TheCase(); theCase != nil {
+		if _, found := b.byCase[theCase.Identifier()]; !found {
+			b.byCase[theCase.Identifier()] = legacyCode
+		}
+	} else if _, found := b.byCode[template.Code()]; !found {
+		b.byCode[template.Code()] = legacyCode
+	}
+}
+
+// FromLegacy returns a status derived from the template mapped to given legacy code, recording the
+// legacy code as a detail. An unmapped legacy code yields an Unknown status.
+func (b *Bridge) FromLegacy(legacyCode string) *opstatus.Status {
+	b.mu.RLock()
+	template, found := b.toStatus[legacyCode]
+	b.mu.RUnlock()
+	if !found {
+		return opstatus.StatusUnknown.WithDescriptionf("unknown legacy error code %s", legacyCode).
+			WithDetails(DetailKeyLegacyCode, legacyCode)
+	}
+	return template.WithDetails(DetailKeyLegacyCode, legacyCode)
+}
+
+// ToLegacy returns the legacy code to answer legacy clients with for given status: the one
+// recorded by FromLegacy, else the one mapped to its case, else the one mapped to its code.
+func (b *Bridge) ToLegacy(status *opstatus.Status) (string, bool) {
+	if legacyCode, found := status.Detail(DetailKeyLegacyCode); found {
+		if code, ok := legacyCode.(string); ok {
+			return code, true
+		}
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if theCase := status.TheCase(); theCase != nil {
+		if legacyCode, found := b.byCase[theCase.Identifier()]; found {
+			return legacyCode, true
+		}
+	}
+	legacyCode, found := b.byCode[status.Code()]
+	return legacyCode, found
+}
+
+// LoadCSV reads mappings from a CSV document with the columns legacy_code, code, case and
+// description, the last two being optional, e.g.:
+//
+//	ERR_1042,NotFound,order_not_found,order not found
+//
+// The code is a code name as returned by Code.Name. A header line starting with legacy_code is
+// skipped. All the malformed lines are reported together.
+func LoadCSV(r io.Reader) (*Bridge, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read legacy mappings: %w", err)
+	}
+
+	bridge := NewBridge()
+	var errs []error
+	for i, record := range records {
+		if i == 0 && len(record) > 0 && strings.EqualFold(record[0], "legacy_code") {
+			continue
+		}
+		if len(record) < 2 || record[0] == "" {
+			errs = append(errs, fmt.Errorf("line %d: expected at least a legacy code and a code", i+1))
+			continue
+		}
+		code, found := opstatus.CodeByName(record[1])
+		if !found {
+			errs = append(errs, fmt.Errorf("line %d: unknown code %q", i+1, record[1]))
+			continue
+		}
+		template := opstatus.NewWithCode(code).WithDescription("")
+		if len(record) > 2 && record[2] != "" {
+			template = template.WithCase(opstatus.CaseID(record[2]))
+		}
+		if len(record) > 3 {
+			template = template.WithDescription(record[3])
+		}
+		bridge.Map(record[0], template)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return bridge, nil
+}
@@ -0,0 +1,38 @@
+package legacy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ikonglong/op-status"
+)
+
+func TestToLegacyKeepsCasesAndCodesApart(t *testing.T) {
+	// The case identifier equals the name of the code of the other mapping.
+	bridge, err := LoadCSV(strings.NewReader("legacy_code,code,case\nERR_1,NotFound,InternalError\nERR_2,InternalError\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := bridge.ToLegacy(opstatus.StatusInternal.WithDescription("boom")); got != "ERR_2" {
+		t.Errorf("ToLegacy(Internal) = %s, want ERR_2", got)
+	}
+	if got, _ := bridge.ToLegacy(opstatus.StatusNotFound.WithCase(opstatus.CaseID("InternalError"))); got != "ERR_1" {
+		t.Errorf("ToLegacy(case InternalError) = %s, want ERR_1", got)
+	}
+}
+
+func TestMapKeepsACopyOfTheTemplate(t *testing.T) {
+	template := opstatus.StatusNotFound.WithDescription("order not found")
+	bridge := NewBridge()
+	bridge.Map("ERR_1042", template)
+	template.AddDetail("order_id", "o-1")
+
+	status := bridge.FromLegacy("ERR_1042")
+	if _, found := status.Detail("order_id"); found {
+		t.Error("the mapping changed with the template")
+	}
+	status.AddDetail("attempt", 2)
+	if _, found := bridge.FromLegacy("ERR_1042").Detail("attempt"); found {
+		t.Error("the mapping changed with a status it returned")
+	}
+}
@@ -0,0 +1,88 @@
+package opstatus
+
+import (
+	"fmt"
+
+	"github.com/ikonglong/op-status/http"
+)
+
+// MappingProfile is a named preset of the code to HTTP status mapping, so that services of a
+// platform standardize on one table instead of maintaining custom ones.
+type MappingProfile struct {
+	Name string
+	// HTTPStatuses overrides the default mapping of the listed codes. The codes not listed keep
+	// their default HTTP status.
+	HTTPStatuses map[Code]http.Status
+}
+
+var (
+	// ProfileGoogle is the mapping of the Google API Improvement Proposals (AIP-193), which is the
+	// default mapping of this package.
+	ProfileGoogle = MappingProfile{Name: "google"}
+
+	// ProfilePragmaticREST maps the semantic failures to the more specific HTTP statuses REST
+	// clients expect: FailedPrecondition to 422 Unprocessable Entity and Aborted, e.g., an ETag
+	// mismatch, to 412 Precondition Failed. 410 Gone and 412 are parsed back by NewByHTTPStatus as
+	// NotFound and FailedPrecondition respectively.
+	ProfilePragmaticREST = MappingProfile{
+		Name: "rest-pragmatic",
+		HTTPStatuses: map[Code]http.Status{
+			CodeFailedPrecondition: http.StatusUnprocessableEntity,
+			CodeAborted:            http.StatusPreconditionFailed,
+		},
+	}
+
+	// ProfileLegacy collapses the failures to the coarse statuses of older gateways: 400 for the
+	// client-side ones and 500 for the server-side ones, but 503 for Unavailable.
+	ProfileLegacy = MappingProfile{
+		Name: "legacy",
+		HTTPStatuses: map[Code]http.Status{
+			CodeUnauthenticated:   http.StatusBadRequest,
+			CodePermissionDenied:  http.StatusBadRequest,
+			CodeNotFound:          http.StatusBadRequest,
+			CodeAborted:           http.StatusBadRequest,
+			CodeAlreadyExists:     http.StatusBadRequest,
+			CodeResourceExhausted: http.StatusBadRequest,
+			CodeCancelled:         http.StatusBadRequest,
+			CodeUnimplemented:     http.StatusInternalServerError,
+			CodeDeadlineExceeded:  http.StatusInternalServerError,
+		},
+	}
+)
+
+var mappingProfiles = map[string]MappingProfile{
+	ProfileGoogle.Name:        ProfileGoogle,
+	ProfilePragmaticREST.Name: ProfilePragmaticREST,
+	ProfileLegacy.Name:        ProfileLegacy,
+}
+
+// MappingProfileByName returns the built-in profile with given name, e.g., read from a service's
+// configuration.
+func MappingProfileByName(name string) (MappingProfile, bool) {
+	profile, found := mappingProfiles[name]
+	return profile, found
+}
+
+// UseMappingProfile makes given profile the base of the code to HTTP status mapping, discarding the
+// overrides previously made with MapToHTTPStatus or UseUnprocessableEntity. The overrides of the
+// registry documents still apply on top of it. The profile is kept by ReloadRegistry, which only
+// replaces the overrides of the registry documents. The change is atomic. It returns ErrFrozen once
+// the configuration is frozen.
+func UseMappingProfile(profile MappingProfile) error {
+	if err := checkNotFrozen(); err != nil {
+		return err
+	}
+	for code, status := range profile.HTTPStatuses {
+		if _, found := codeToHTTPStatus[code]; !found {
+			return fmt.Errorf("mapping profile %q: unknown op status code %v", profile.Name, code)
+		}
+		if !http.IsDefined(status.Code()) {
+			return fmt.Errorf("mapping profile %q: HTTP status %d is not defined", profile.Name, status)
+		}
+	}
+	updateHTTPMapping(func() {
+		httpProfile = profile
+		httpOverrides = map[Code]http.Status{}
+	})
+	return nil
+}
@@ -0,0 +1,44 @@
+package opstatus
+
+// MergePolicy decides which value wins when merged statuses have a detail with the same key.
+type MergePolicy int
+
+const (
+	// KeepExisting keeps the detail value of the status merged into.
+	KeepExisting MergePolicy = iota
+	// PreferOther takes the detail value of the status being merged.
+	PreferOther
+)
+
+// Merge returns a derived instance of this Status that adds the context of the other status: the
+// other description is appended to this one, the details are united, resolving key conflicts with
+// the given policy, and the other case and cause are taken only if this status has none. The code
+// of this status is kept, and the other description isn't appended if equal to this one. Merging a
+// nil status returns a plain copy of this one.
+//
+// It is meant for a higher layer adding context to a status returned by a lower layer without
+// flattening it to strings.
+func (s *Status) Merge(other *Status, policy MergePolicy) *Status {
+	if other == nil {
+		return s.derive()
+	}
+	description := other.description
+	if description == s.description {
+		description = ""
+	}
+	merged := s.AugmentDescription(description)
+	merged.details = copyDetails(merged.details)
+	for key, value := range other.details {
+		if _, exists := merged.details[key]; exists && policy == KeepExisting {
+			continue
+		}
+		merged.details[key] = value
+	}
+	if merged.theCase == nil {
+		merged.theCase = other.theCase
+	}
+	if merged.cause == nil {
+		merged.cause = other.cause
+	}
+	return merged
+}
@@ -0,0 +1,23 @@
+package opstatus
+
+import "testing"
+
+func TestMergeOfANilStatus(t *testing.T) {
+	status := StatusNotFound.WithDescription("order not found")
+	merged := status.Merge(nil, KeepExisting)
+	if merged == status || merged.Description() != "order not found" {
+		t.Errorf("Merge(nil) = %p %q, want a copy of %p", merged, merged.Description(), status)
+	}
+}
+
+func TestMergeDoesNotRepeatAnEqualDescription(t *testing.T) {
+	status := StatusUnavailable.WithDescription("inventory is down")
+	merged := status.Merge(StatusUnavailable.WithDescription("inventory is down"), KeepExisting)
+	if merged.Description() != "inventory is down" {
+		t.Errorf("Description() = %q, want it once", merged.Description())
+	}
+	merged = status.Merge(StatusInternal.WithDescription("pool exhausted"), KeepExisting)
+	if want := "inventory is down\npool exhausted"; merged.Description() != want {
+		t.Errorf("Description() = %q, want %q", merged.Description(), want)
+	}
+}
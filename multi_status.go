@@ -0,0 +1,84 @@
+package opstatus
+
+import "strings"
+
+// DetailKeyFailures is the detail key of the error conditions of all the failures summarized by
+// MultiStatus.Status.
+const DetailKeyFailures = ReservedDetailKeyPrefix + "failures"
+
+// Step is a step of a pipeline. It returns nil or an OK status on success.
+type Step func() *Status
+
+// First runs given steps in order and stops at the first failing one, returning its status. If all
+// the steps succeed, an OK status is returned.
+func First(steps ...Step) *Status {
+	for _, step := range steps {
+		if status := step(); status != nil && !status.IsOK() {
+			return status
+		}
+	}
+	return StatusOK.derive()
+}
+
+// All runs all the given steps and collects the statuses of the failing ones.
+func All(steps ...Step) *MultiStatus {
+	multi := &MultiStatus{}
+	for _, step := range steps {
+		multi.Add(step())
+	}
+	return multi
+}
+
+// MultiStatus collects the statuses of several failures, e.g., of the steps of a validation
+// pipeline or of the items of a batch.
+type MultiStatus struct {
+	statuses []*Status
+}
+
+// Add adds given status unless it is nil or OK.
+func (m *MultiStatus) Add(status *Status) {
+	if status == nil || status.IsOK() {
+		return
+	}
+	m.statuses = append(m.statuses, status)
+}
+
+// Statuses returns the collected statuses in the order they were added.
+func (m *MultiStatus) Statuses() []*Status {
+	return m.statuses
+}
+
+// Len returns the number of collected statuses.
+func (m *MultiStatus) Len() int {
+	return len(m.statuses)
+}
+
+// IsOK tells if no failure has been collected.
+func (m *MultiStatus) IsOK() bool {
+	return len(m.statuses) == 0
+}
+
+// Status summarizes the collected failures in a single status. It is OK if there are none, the
+// only status if there is one, and otherwise a status with the code of the first failure, whose
+// description joins all the descriptions and whose DetailKeyFailures detail lists the error
+// condition of every failure.
+func (m *MultiStatus) Status() *Status {
+	switch len(m.statuses) {
+	case 0:
+		return StatusOK.derive()
+	case 1:
+		return m.statuses[0]
+	}
+	descriptions := make([]string, 0, len(m.statuses))
+	conditions := make([]string, 0, len(m.statuses))
+	for _, status := range m.statuses {
+		if status.description != "" {
+			descriptions = append(descriptions, status.description)
+		}
+		conditions = append(conditions, status.ToErrorCondition())
+	}
+	summary := m.statuses[0].derive()
+	summary.description = strings.Join(descriptions, "; ")
+	summary.setDetail(DetailKeyFailures, conditions)
+	return summary
+}
@@ -0,0 +1,84 @@
+// Package oauth2status translates OAuth 2.0 and OpenID Connect error responses (RFC 6749, RFC 6750,
+// RFC 8628 and OIDC Core) into operation statuses.
+package oauth2status
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ikonglong/op-status"
+)
+
+// Detail keys of the OAuth 2.0 error metadata recorded by the translators.
+const (
+	DetailKeyError    = "oauth2_error"
+	DetailKeyErrorURI = "oauth2_error_uri"
+)
+
+// DefaultSlowDownDelay is the retry delay given to slow_down errors, the polling interval increase
+// required by RFC 8628.
+const DefaultSlowDownDelay = 5 * time.Second
+
+var errorToCode = map[string]opstatus.Code{
+	"invalid_request":           opstatus.CodeInvalidArgument,
+	"invalid_client":            opstatus.CodeUnauthenticated,
+	"invalid_grant":             opstatus.CodeUnauthenticated,
+	"unauthorized_client":       opstatus.CodePermissionDenied,
+	"unsupported_grant_type":    opstatus.CodeInvalidArgument,
+	"unsupported_response_type": opstatus.CodeInvalidArgument,
+	"invalid_scope":             opstatus.CodeInvalidArgument,
+	"access_denied":             opstatus.CodePermissionDenied,
+	"invalid_token":             opstatus.CodeUnauthenticated,
+	"insufficient_scope":        opstatus.CodePermissionDenied,
+	"server_error":              opstatus.CodeInternal,
+	"temporarily_unavailable":   opstatus.CodeUnavailable,
+	"authorization_pending":     opstatus.CodeUnavailable,
+	"slow_down":                 opstatus.CodeResourceExhausted,
+	"expired_token":             opstatus.CodeUnauthenticated,
+	"login_required":            opstatus.CodeUnauthenticated,
+	"interaction_required":      opstatus.CodeUnauthenticated,
+	"consent_required":          opstatus.CodePermissionDenied,
+}
+
+// FromError returns the status for given OAuth 2.0 error code, description and URI, e.g.,
+// invalid_token yields Unauthenticated and insufficient_scope yields PermissionDenied. slow_down
+// yields ResourceExhausted with a RetryInfo of DefaultSlowDownDelay. Unknown error codes yield
+// Unknown. The error code and URI are recorded as details.
+func FromError(errorCode, description, errorURI string) *opstatus.Status {
+	code, found := errorToCode[errorCode]
+	if !found {
+		code = opstatus.CodeUnknown
+	}
+	if description == "" {
+		description = errorCode
+	}
+	status := opstatus.NewWithCode(code).WithDescription(description).WithDetails(DetailKeyError, errorCode)
+	if errorURI != "" {
+		status = status.WithDetails(DetailKeyErrorURI, errorURI)
+	}
+	if errorCode == "slow_down" {
+		status = status.WithRetryDelay(DefaultSlowDownDelay)
+	}
+	return status
+}
+
+// errorResponse is the error response body of RFC 6749, section 5.2.
+type errorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+	ErrorURI         string `json:"error_uri"`
+}
+
+// FromResponseBody returns the status for given JSON error response body of a token or
+// authorization endpoint.
+func FromResponseBody(body []byte) (*opstatus.Status, error) {
+	var resp errorResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("decode OAuth 2.0 error response: %w", err)
+	}
+	if resp.Error == "" {
+		return nil, fmt.Errorf("OAuth 2.0 error response has no error code")
+	}
+	return FromError(resp.Error, resp.ErrorDescription, resp.ErrorURI), nil
+}
@@ -0,0 +1,61 @@
+package opstatus
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Observer is notified of every status an OpError is created with, e.g., to check invariants on
+// the statuses produced by the code under test.
+type Observer func(s *Status)
+
+type observerEntry struct {
+	observe Observer
+}
+
+var (
+	// observersMu serializes the updates of observers, which are copy-on-write so that the creation
+	// of OpErrors never locks.
+	observersMu sync.Mutex
+	observers   atomic.Pointer[[]*observerEntry]
+)
+
+// Observe registers given observer and returns the function unregistering it. Observers are not
+// subject to Freeze, and they run synchronously: they slow down the creation of OpErrors.
+func Observe(observe Observer) (stop func()) {
+	entry := &observerEntry{observe: observe}
+	updateObservers(func(list []*observerEntry) []*observerEntry {
+		return append(list, entry)
+	})
+	return func() {
+		updateObservers(func(list []*observerEntry) []*observerEntry {
+			for i, e := range list {
+				if e == entry {
+					return append(list[:i], list[i+1:]...)
+				}
+			}
+			return list
+		})
+	}
+}
+
+func updateObservers(update func(list []*observerEntry) []*observerEntry) {
+	observersMu.Lock()
+	defer observersMu.Unlock()
+	var list []*observerEntry
+	if current := observers.Load(); current != nil {
+		list = append(list, *current...)
+	}
+	list = update(list)
+	observers.Store(&list)
+}
+
+func notifyObservers(s *Status) {
+	current := observers.Load()
+	if current == nil {
+		return
+	}
+	for _, entry := range *current {
+		entry.observe(s)
+	}
+}
@@ -0,0 +1,37 @@
+package opstatus
+
+// OpError is an error reporting the Status of a failed operation. It wraps the cause of the status.
+type OpError struct {
+	status *Status
+}
+
+// NewOpError returns an OpError with given status, of which the registered observers are notified.
+func NewOpError(status Status) *OpError {
+	notifyObservers(&status)
+	return &OpError{
+		status: &status,
+	}
+}
+
+func (e *OpError) Status() *Status {
+	return e.status
+}
+
+func (e *OpError) Cause() error {
+	return e.status.cause
+}
+
+// Unwrap returns the cause of this error, so that the errors package can inspect the causal chain.
+func (e *OpError) Unwrap() error {
+	return e.status.cause
+}
+
+// Error describes the error condition of the status. The cause is only appended if the status has
+// no description, as the description usually tells it already.
+func (e *OpError) Error() string {
+	condition := e.status.ToErrorCondition()
+	if e.status.cause == nil || e.status.description != "" {
+		return condition
+	}
+	return condition + ": " + e.status.cause.Error()
+}
@@ -0,0 +1,58 @@
+package opstatus
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+)
+
+// DetailKeyOperationID is the detail key of the operation ID attached by WithOperationIDFrom.
+const DetailKeyOperationID = ReservedDetailKeyPrefix + "operation_id"
+
+type operationIDKey struct{}
+
+// NewOperationID returns a random operation ID.
+func NewOperationID() string {
+	var id [16]byte
+	_, _ = io.ReadFull(randomReader(), id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// ContextWithOperationID returns a copy of the parent context carrying given operation ID.
+func ContextWithOperationID(parent context.Context, id string) context.Context {
+	return context.WithValue(parent, operationIDKey{}, id)
+}
+
+// OperationIDFromContext returns the operation ID carried by given context, if any.
+func OperationIDFromContext(ctx context.Context) (string, bool) {
+	id, found := ctx.Value(operationIDKey{}).(string)
+	return id, found && id != ""
+}
+
+// EnsureOperationID returns given context and the operation ID it carries, or, if it carries none,
+// a copy of it carrying a new one. Calling it once at the start of a request and logging the ID
+// with it guarantees that the logs and the error payload share the same ID.
+func EnsureOperationID(ctx context.Context) (context.Context, string) {
+	if id, found := OperationIDFromContext(ctx); found {
+		return ctx, id
+	}
+	id := NewOperationID()
+	return ContextWithOperationID(ctx, id), id
+}
+
+// WithOperationIDFrom returns a derived instance of this Status carrying the operation ID of given
+// context, so that support can join a customer's report to the logs. If the context carries no
+// operation ID, a copy of this status is returned.
+func (s *Status) WithOperationIDFrom(ctx context.Context) *Status {
+	derived := s.derive()
+	if id, found := OperationIDFromContext(ctx); found {
+		derived.setDetail(DetailKeyOperationID, id)
+	}
+	return derived
+}
+
+// OperationID returns the operation ID attached to this status, if any.
+func (s *Status) OperationID() (string, bool) {
+	id, found := s.details[DetailKeyOperationID].(string)
+	return id, found
+}
@@ -0,0 +1,120 @@
+package opstatustest
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/ikonglong/op-status"
+)
+
+// FakeClock is a deterministic opstatus.Clock. Its time only moves when Advance is called or when
+// something waits on it: After advances the clock by the waited duration and fires at once, so
+// that, e.g., retries with backoff run without delay. It is safe for concurrent use.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock returns a FakeClock set to given time.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// UseFakeClock makes a FakeClock set to given time the clock of the opstatus package until the end
+// of the test.
+func UseFakeClock(t TB, now time.Time) *FakeClock {
+	t.Helper()
+	clock := NewFakeClock(now)
+	if err := opstatus.SetClock(clock); err != nil {
+		t.Errorf("use fake clock: %v", err)
+	}
+	t.Cleanup(func() { opstatus.SetClock(nil) })
+	return clock
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	fired := make(chan time.Time, 1)
+	fired <- c.Advance(d)
+	return fired
+}
+
+// Advance moves the clock forward by given duration and returns the new time.
+func (c *FakeClock) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	return c.now
+}
+
+// SequentialRandom is a deterministic source of randomness yielding sequential operation IDs: the
+// n-th ID returned by opstatus.NewOperationID is SequentialID(n), starting at 1. It is safe for
+// concurrent use.
+type SequentialRandom struct {
+	mu   sync.Mutex
+	next uint64
+}
+
+// UseRedactedDetails marks the details with given keys as sensitive with opstatus.RedactDetails until
+// the end of the test. The keys that were already marked stay so.
+func UseRedactedDetails(t TB, keys ...string) {
+	t.Helper()
+	previous := map[string]bool{}
+	for _, key := range opstatus.RedactedDetailKeys() {
+		previous[key] = true
+	}
+	var added []string
+	for _, key := range keys {
+		if key := opstatus.NormalizeDetailKey(key); !previous[key] {
+			added = append(added, key)
+		}
+	}
+	if err := opstatus.RedactDetails(added...); err != nil {
+		t.Errorf("use redacted details: %v", err)
+	}
+	t.Cleanup(func() { opstatus.UnredactDetails(added...) })
+}
+
+// UseSequentialIDs makes a SequentialRandom the source of randomness of the opstatus package until
+// the end of the test.
+func UseSequentialIDs(t TB) *SequentialRandom {
+	t.Helper()
+	random := &SequentialRandom{}
+	if err := opstatus.SetRandom(random); err != nil {
+		t.Errorf("use sequential IDs: %v", err)
+	}
+	t.Cleanup(func() { opstatus.SetRandom(nil) })
+	return random
+}
+
+// Read fills given buffer with zeros followed by the next number of the sequence, big-endian.
+func (r *SequentialRandom) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.next++
+	for i := range p {
+		p[i] = 0
+	}
+	var n [8]byte
+	binary.BigEndian.PutUint64(n[:], r.next)
+	if len(p) >= len(n) {
+		copy(p[len(p)-len(n):], n[:])
+	} else {
+		copy(p, n[len(n)-len(p):])
+	}
+	return len(p), nil
+}
+
+// SequentialID returns the n-th operation ID yielded by a SequentialRandom.
+func SequentialID(n uint64) string {
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[8:], n)
+	return hex.EncodeToString(id[:])
+}
@@ -0,0 +1,58 @@
+package opstatustest
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ikonglong/op-status"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := UseFakeClock(t, start)
+
+	calls := 0
+	status := opstatus.Retry(context.Background(), opstatus.RetryPolicy{MaxAttempts: 3}, func(ctx context.Context) *opstatus.Status {
+		calls++
+		return opstatus.StatusUnavailable.WithDescription("unavailable")
+	})
+
+	if calls != 3 {
+		t.Fatalf("calls = %d, want 3", calls)
+	}
+	// The default backoff waits 1s, then 2s.
+	if got, want := clock.Now(), start.Add(3*time.Second); !got.Equal(want) {
+		t.Errorf("Now() = %v, want %v", got, want)
+	}
+	attempts, found := status.Detail(opstatus.DetailKeyAttempts)
+	if !found || attempts.(opstatus.AttemptsInfo).Elapsed != 3*time.Second {
+		t.Errorf("attempts = %+v, want an elapsed time of 3s", attempts)
+	}
+}
+
+func TestSequentialIDs(t *testing.T) {
+	UseSequentialIDs(t)
+	for n := uint64(1); n <= 3; n++ {
+		if got, want := opstatus.NewOperationID(), SequentialID(n); got != want {
+			t.Errorf("NewOperationID() = %s, want %s", got, want)
+		}
+	}
+	if got, want := SequentialID(2), "00000000000000000000000000000002"; got != want {
+		t.Errorf("SequentialID(2) = %s, want %s", got, want)
+	}
+}
+
+func TestUseRedactedDetailsRestoresTheConfiguration(t *testing.T) {
+	before := opstatus.RedactedDetailKeys()
+	t.Run("test", func(t *testing.T) {
+		UseRedactedDetails(t, "api_key")
+		if !slices.Contains(opstatus.RedactedDetailKeys(), "api_key") {
+			t.Errorf("RedactedDetailKeys() = %v, want api_key in it", opstatus.RedactedDetailKeys())
+		}
+	})
+	if after := opstatus.RedactedDetailKeys(); !slices.Equal(after, before) {
+		t.Errorf("RedactedDetailKeys() = %v after the test, want %v", after, before)
+	}
+}
@@ -0,0 +1,106 @@
+package opstatustest
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/ikonglong/op-status"
+)
+
+const modulePath = "github.com/ikonglong/op-status"
+
+// Invariant checks a produced status. It returns a description of the violation, if any.
+type Invariant func(s *opstatus.Status) error
+
+// DescribedClientErrors requires the statuses rendered as 4xx to have a description, since clients
+// are expected to fix their requests from it.
+func DescribedClientErrors(s *opstatus.Status) error {
+	if httpStatus := s.HTTPStatus(); httpStatus >= 400 && httpStatus < 500 && s.Description() == "" {
+		return fmt.Errorf("%v status rendered as %d has no description", s.Code(), httpStatus)
+	}
+	return nil
+}
+
+// CasedBusinessErrors requires the statuses with one of given codes, the ones the service reports
+// business errors with, to have a case.
+func CasedBusinessErrors(codes ...opstatus.Code) Invariant {
+	return func(s *opstatus.Status) error {
+		for _, code := range codes {
+			if s.Code() == code && s.TheCase() == nil {
+				return fmt.Errorf("%v status has no case", code)
+			}
+		}
+		return nil
+	}
+}
+
+// NoSensitiveDetails forbids the statuses to carry the details marked as sensitive with
+// opstatus.RedactDetails, since the statuses of OpErrors reach clients.
+func NoSensitiveDetails(s *opstatus.Status) error {
+	if s.HasSensitiveDetails() {
+		return fmt.Errorf("%v status carries sensitive details", s.Code())
+	}
+	return nil
+}
+
+// TB is the subset of testing.TB used by CheckInvariants.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Cleanup(func())
+}
+
+// CheckInvariants checks given invariants on every status an OpError is created with until the
+// end of the test, then fails the test listing the violations with the source locations that
+// produced them. Since observers are package-wide, tests using it must not run in parallel.
+func CheckInvariants(t TB, invariants ...Invariant) {
+	t.Helper()
+	var (
+		mu         sync.Mutex
+		violations []string
+	)
+	stop := opstatus.Observe(func(s *opstatus.Status) {
+		for _, invariant := range invariants {
+			if err := invariant(s); err != nil {
+				mu.Lock()
+				violations = append(violations, fmt.Sprintf("%s: %v", callerLocation(), err))
+				mu.Unlock()
+			}
+		}
+	})
+	t.Cleanup(func() {
+		stop()
+		mu.Lock()
+		defer mu.Unlock()
+		if len(violations) > 0 {
+			t.Errorf("op status invariants violated:\n\t%s", strings.Join(violations, "\n\t"))
+		}
+	})
+}
+
+// callerLocation returns the location of the first caller outside this module's packages building
+// OpErrors.
+func callerLocation() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !isInternalFrame(frame.Function) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return "unknown location"
+		}
+	}
+}
+
+func isInternalFrame(function string) bool {
+	for _, pkg := range []string{modulePath, modulePath + "/error", modulePath + "/opstatustest"} {
+		if strings.HasPrefix(function, pkg+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package opstatustest
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ikonglong/op-status"
+)
+
+func TestNoSensitiveDetails(t *testing.T) {
+	UseRedactedDetails(t, "password")
+	withNaN := opstatus.StatusInternal.WithDescription("failed")
+	withNaN.AddDetail("ratio", math.NaN())
+	withFunc := opstatus.StatusInternal.WithDescription("failed")
+	withFunc.AddDetail("callback", func() {})
+	withPassword := opstatus.StatusUnauthenticated.WithDescription("failed")
+	withPassword.AddDetail("password", "secret")
+	withNestedPassword := opstatus.StatusUnauthenticated.WithDescription("failed")
+	withNestedPassword.AddDetail("credentials", map[string]any{"password": "secret"})
+
+	tests := []struct {
+		name   string
+		status *opstatus.Status
+		want   bool
+	}{
+		{"no details", &opstatus.StatusNotFound, false},
+		{"NaN detail", withNaN, false},
+		{"func detail", withFunc, false},
+		{"sensitive detail", withPassword, true},
+		{"nested sensitive detail", withNestedPassword, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NoSensitiveDetails(tt.status) != nil; got != tt.want {
+				t.Errorf("NoSensitiveDetails() reported a violation: %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
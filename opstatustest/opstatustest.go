@@ -0,0 +1,53 @@
+// Package opstatustest provides factories to tersely build expected statuses and OpErrors in
+// table-driven tests.
+package opstatustest
+
+import (
+	"github.com/ikonglong/op-status"
+	operr "github.com/ikonglong/op-status/error"
+)
+
+// Option customizes a status built by Status or Err.
+type Option func(s *opstatus.Status) *opstatus.Status
+
+// WithCase sets the case of the status.
+func WithCase(theCase opstatus.Case) Option {
+	return func(s *opstatus.Status) *opstatus.Status {
+		return s.WithCase(theCase)
+	}
+}
+
+// WithDescription sets the description of the status.
+func WithDescription(description string) Option {
+	return func(s *opstatus.Status) *opstatus.Status {
+		return s.WithDescription(description)
+	}
+}
+
+// WithDetail adds a detail to the status.
+func WithDetail(key string, value any) Option {
+	return func(s *opstatus.Status) *opstatus.Status {
+		s.AddDetail(key, value)
+		return s
+	}
+}
+
+// Status returns a status with given code customized by given options.
+func Status(code opstatus.Code, opts ...Option) *opstatus.Status {
+	// Derive a copy so that options never mutate the shared prototype.
+	s := opstatus.NewWithCode(code).WithDescription("")
+	for _, opt := range opts {
+		s = opt(s)
+	}
+	return s
+}
+
+// Err returns an OpError with a status built as Status does.
+func Err(code opstatus.Code, opts ...Option) *operr.OpError {
+	return operr.NewWithStatus(*Status(code, opts...))
+}
+
+// ErrWithCause returns an OpError caused by given error with a status built as Status does.
+func ErrWithCause(code opstatus.Code, cause error, opts ...Option) *operr.OpError {
+	return operr.NewWithStatusAndCause(*Status(code, opts...), cause)
+}
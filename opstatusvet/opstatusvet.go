@@ -0,0 +1,409 @@
+// Package opstatusvet provides an analyzer reporting misuses of the op-status taxonomy in consuming
+// code:
+//
+//   - AddDetail/AddDetails called on a package-level Status prototype, or on a variable holding one
+//     or its address, which mutates it for everyone
+//   - assignments to a package-level Status prototype, directly or through a pointer to it
+//   - argument slices passed to a formatting function of op-status, e.g., WithDescriptionf, without
+//     "...", which formats the slice as a single argument
+//   - statuses given an empty description and turned into an error right away, e.g.,
+//     StatusNotFound.WithDescription("").Err(); the WithDescription("") copy idiom alone is fine
+//   - StatusInternal used for what looks like a validation error
+//   - OpErrors built without the error that caused them
+//
+// The checks rely on type information, so that renamed and dot imports are handled. Each finding
+// comes with a suggestion, given as a suggested fix when it is mechanical.
+package opstatusvet
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const (
+	opstatusPath = "github.com/ikonglong/op-status"
+	operrPath    = "github.com/ikonglong/op-status/error"
+)
+
+// Analyzer reports misuses of the op-status taxonomy. It can be run by cmd/opstatus-vet, directly
+// or as a go vet -vettool, or be added to a multichecker.
+var Analyzer = &analysis.Analyzer{
+	Name: "opstatus",
+	Doc:  "report misuses of the op-status taxonomy, e.g., mutations of the shared Status prototypes",
+	Run:  run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	// The package itself builds its prototypes.
+	if pass.Pkg.Path() == opstatusPath {
+		return nil, nil
+	}
+	for _, file := range pass.Files {
+		c := &checker{pass: pass, aliases: prototypeAliases(pass, file)}
+		c.visit(file, nil)
+	}
+	return nil, nil
+}
+
+type checker struct {
+	pass *analysis.Pass
+	// aliases are the variables holding a prototype or its address.
+	aliases map[*types.Var]*types.Var
+}
+
+// visit checks given node. errVar is the error checked not to be nil by the enclosing if
+// statement, if any.
+func (c *checker) visit(root ast.Node, errVar *ast.Ident) {
+	ast.Inspect(root, func(node ast.Node) bool {
+		if ifStmt, ok := node.(*ast.IfStmt); ok && node != root {
+			if ifStmt.Init != nil {
+				c.visit(ifStmt.Init, errVar)
+			}
+			c.visit(ifStmt.Cond, errVar)
+			if checked := c.errNotNil(ifStmt.Cond); checked != nil {
+				c.visit(ifStmt.Body, checked)
+			} else {
+				c.visit(ifStmt.Body, errVar)
+			}
+			if ifStmt.Else != nil {
+				c.visit(ifStmt.Else, errVar)
+			}
+			return false
+		}
+		switch node := node.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				c.checkAssignment(lhs)
+			}
+		case *ast.CallExpr:
+			c.checkCall(node, errVar)
+		}
+		return true
+	})
+}
+
+func (c *checker) checkAssignment(lhs ast.Expr) {
+	target := ast.Unparen(lhs)
+	if star, ok := target.(*ast.StarExpr); ok {
+		target = star.X
+	}
+	var prototype *types.Var
+	if target == ast.Unparen(lhs) {
+		prototype = c.prototypeOf(target)
+	} else {
+		prototype = c.prototype(target)
+	}
+	if prototype == nil {
+		return
+	}
+	c.reportf(lhs, "declare a status of your own, e.g., "+prototype.Name()+".WithDescription(...)",
+		"assignment overwrites the shared Status prototype %s", prototype.Name())
+}
+
+func (c *checker) checkCall(call *ast.CallExpr, errVar *ast.Ident) {
+	fn := c.callee(call)
+	if fn == nil || fn.Pkg() == nil {
+		return
+	}
+	c.checkArgSlice(call, fn)
+	switch fn.Pkg().Path() {
+	case opstatusPath:
+		c.checkMethodCall(call, fn)
+	case operrPath:
+		switch fn.Name() {
+		case "NewWithStatus", "NewWithStatusAndCause", "Wrap", "Wrapf":
+			for _, arg := range call.Args {
+				if c.isEmptyDescription(arg) {
+					c.reportf(call, "describe the error condition", "status is given an empty description")
+				}
+			}
+		}
+		if fn.Name() == "NewWithStatus" && errVar != nil && len(call.Args) == 1 {
+			c.reportLostCause(call, errVar)
+		}
+	}
+}
+
+func (c *checker) checkMethodCall(call *ast.CallExpr, method *types.Func) {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok || !isStatusMethod(method) {
+		return
+	}
+	switch name := method.Name(); name {
+	case "AddDetail", "AddDetails":
+		if prototype := c.prototype(sel.X); prototype != nil {
+			c.reportf(call, "derive a status first, e.g., "+prototype.Name()+".WithDescription(...)."+name+"(...)",
+				"%s mutates the shared Status prototype %s", name, prototype.Name())
+		}
+	case "Err":
+		if c.isEmptyDescription(sel.X) {
+			c.reportf(call, "describe the error condition", "status is given an empty description")
+		}
+	case "WithDescription", "WithDescriptionf":
+		if prototype := c.prototypeOf(sel.X); prototype != nil && prototype.Name() == "StatusInternal" &&
+			len(call.Args) > 0 && looksLikeValidation(c.constantString(call.Args[0])) {
+			c.reportf(call, "use StatusInvalidArgument or StatusFailedPrecondition",
+				"StatusInternal is used for what looks like a validation error")
+		}
+	}
+}
+
+// checkArgSlice reports the argument slices passed without "..." as the formatting arguments of the
+// variadic functions of op-status.
+func (c *checker) checkArgSlice(call *ast.CallExpr, fn *types.Func) {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || !sig.Variadic() || call.Ellipsis.IsValid() || len(call.Args) < sig.Params().Len() {
+		return
+	}
+	last := call.Args[len(call.Args)-1]
+	if !isArgSlice(c.pass.TypesInfo.TypeOf(last)) {
+		return
+	}
+	c.pass.Report(analysis.Diagnostic{
+		Pos:     call.Pos(),
+		End:     call.End(),
+		Message: "argument slice is formatted as a single argument",
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message:   "spread it: " + types.ExprString(last) + "...",
+			TextEdits: []analysis.TextEdit{{Pos: last.End(), End: last.End(), NewText: []byte("...")}},
+		}},
+	})
+}
+
+func (c *checker) reportLostCause(call *ast.CallExpr, errVar *ast.Ident) {
+	var name *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		name = fun
+	case *ast.SelectorExpr:
+		name = fun.Sel
+	default:
+		return
+	}
+	c.pass.Report(analysis.Diagnostic{
+		Pos:     call.Pos(),
+		End:     call.End(),
+		Message: "OpError is built without the error that caused it",
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message: "use NewWithStatusAndCause(status, " + errVar.Name + ")",
+			TextEdits: []analysis.TextEdit{
+				{Pos: name.Pos(), End: name.End(), NewText: []byte("NewWithStatusAndCause")},
+				{Pos: call.Rparen, End: call.Rparen, NewText: []byte(", " + errVar.Name)},
+			},
+		}},
+	})
+}
+
+// reportf reports a finding whose suggestion can't be applied mechanically, so it is part of the
+// message.
+func (c *checker) reportf(node ast.Node, suggestion, format string, args ...any) {
+	c.pass.Report(analysis.Diagnostic{
+		Pos:     node.Pos(),
+		End:     node.End(),
+		Message: fmt.Sprintf(format, args...) + "; " + suggestion,
+	})
+}
+
+// callee returns the function or method called by given call, if statically known.
+func (c *checker) callee(call *ast.CallExpr) *types.Func {
+	var ident *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil
+	}
+	fn, _ := c.pass.TypesInfo.Uses[ident].(*types.Func)
+	return fn
+}
+
+// prototype returns the prototype given expression denotes, by name or through a variable holding
+// it or its address, possibly taking its address.
+func (c *checker) prototype(expr ast.Expr) *types.Var {
+	expr = ast.Unparen(expr)
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = ast.Unparen(unary.X)
+	}
+	if prototype := c.prototypeOf(expr); prototype != nil {
+		return prototype
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		if v, ok := c.pass.TypesInfo.Uses[ident].(*types.Var); ok {
+			return c.aliases[v]
+		}
+	}
+	return nil
+}
+
+// prototypeOf returns the prototype given expression names, e.g., opstatus.StatusNotFound.
+func (c *checker) prototypeOf(expr ast.Expr) *types.Var {
+	return prototypeOf(c.pass.TypesInfo, expr)
+}
+
+func prototypeOf(info *types.Info, expr ast.Expr) *types.Var {
+	var ident *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	default:
+		return nil
+	}
+	v, ok := info.Uses[ident].(*types.Var)
+	if !ok || v.Pkg() == nil || v.Pkg().Path() != opstatusPath || v.Parent() != v.Pkg().Scope() ||
+		!isStatus(v.Type()) {
+		return nil
+	}
+	return v
+}
+
+// prototypeAliases returns the variables of given file that are only ever given a prototype or its
+// address, e.g., s := &opstatus.StatusNotFound.
+func prototypeAliases(pass *analysis.Pass, file *ast.File) map[*types.Var]*types.Var {
+	aliases := map[*types.Var]*types.Var{}
+	reassigned := map[*types.Var]bool{}
+	record := func(lhs ast.Expr, rhs ast.Expr) {
+		ident, ok := ast.Unparen(lhs).(*ast.Ident)
+		if !ok {
+			return
+		}
+		v, ok := pass.TypesInfo.ObjectOf(ident).(*types.Var)
+		if !ok {
+			return
+		}
+		rhs = ast.Unparen(rhs)
+		if unary, ok := rhs.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			rhs = unary.X
+		}
+		if prototype := prototypeOf(pass.TypesInfo, rhs); prototype != nil && !reassigned[v] {
+			aliases[v] = prototype
+			return
+		}
+		reassigned[v] = true
+		delete(aliases, v)
+	}
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range node.Lhs {
+				var rhs ast.Expr
+				if len(node.Lhs) == len(node.Rhs) {
+					rhs = node.Rhs[i]
+				}
+				record(lhs, rhs)
+			}
+		case *ast.ValueSpec:
+			for i, name := range node.Names {
+				var rhs ast.Expr
+				if len(node.Names) == len(node.Values) {
+					rhs = node.Values[i]
+				}
+				record(name, rhs)
+			}
+		}
+		return true
+	})
+	return aliases
+}
+
+// isEmptyDescription tells if given expression gives a status an empty constant description with
+// WithDescription or WithDescriptionf and no formatting arguments, possibly dereferenced.
+func (c *checker) isEmptyDescription(expr ast.Expr) bool {
+	expr = ast.Unparen(expr)
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = ast.Unparen(star.X)
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return false
+	}
+	method := c.callee(call)
+	if method == nil || !isStatusMethod(method) ||
+		method.Name() != "WithDescription" && method.Name() != "WithDescriptionf" {
+		return false
+	}
+	description, isConstant := c.constant(call.Args[0])
+	return isConstant && strings.TrimSpace(description) == ""
+}
+
+// errNotNil returns the error given condition checks not to be nil, e.g., err in err != nil.
+func (c *checker) errNotNil(cond ast.Expr) *ast.Ident {
+	bin, ok := ast.Unparen(cond).(*ast.BinaryExpr)
+	if !ok || bin.Op != token.NEQ {
+		return nil
+	}
+	ident, ok := ast.Unparen(bin.X).(*ast.Ident)
+	if !ok || !c.pass.TypesInfo.Types[bin.Y].IsNil() {
+		return nil
+	}
+	errorType := types.Universe.Lookup("error").Type()
+	if t := c.pass.TypesInfo.TypeOf(ident); t == nil || !types.Identical(t, errorType) {
+		return nil
+	}
+	return ident
+}
+
+func (c *checker) constant(expr ast.Expr) (string, bool) {
+	value := c.pass.TypesInfo.Types[expr].Value
+	if value == nil || value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(value), true
+}
+
+func (c *checker) constantString(expr ast.Expr) string {
+	s, _ := c.constant(expr)
+	return s
+}
+
+// isStatus tells if given type is opstatus.Status.
+func isStatus(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == opstatusPath && named.Obj().Name() == "Status"
+}
+
+func isStatusMethod(fn *types.Func) bool {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return false
+	}
+	recv := sig.Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	return isStatus(recv)
+}
+
+// isArgSlice tells if given type is a slice of empty interfaces, i.e., of formatting arguments.
+func isArgSlice(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	slice, ok := t.Underlying().(*types.Slice)
+	if !ok {
+		return false
+	}
+	iface, ok := slice.Elem().Underlying().(*types.Interface)
+	return ok && iface.Empty()
+}
+
+var validationWords = []string{"invalid", "required", "must", "malformed", "missing"}
+
+func looksLikeValidation(text string) bool {
+	text = strings.ToLower(text)
+	for _, word := range validationWords {
+		if strings.Contains(text, word) {
+			return true
+		}
+	}
+	return false
+}
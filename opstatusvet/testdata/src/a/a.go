@@ -0,0 +1,52 @@
+package a
+
+import (
+	"os"
+
+	status "github.com/ikonglong/op-status"
+	operr "github.com/ikonglong/op-status/error"
+)
+
+const empty = ""
+
+func prototypes() {
+	status.StatusNotFound.AddDetail("k", "v")             // want `AddDetail mutates the shared Status prototype StatusNotFound`
+	(&status.StatusNotFound).AddDetails(map[string]any{}) // want `AddDetails mutates the shared Status prototype StatusNotFound`
+	status.StatusNotFound = status.Status{}               // want `assignment overwrites the shared Status prototype StatusNotFound`
+
+	p := &status.StatusInternal
+	p.AddDetail("k", "v") // want `AddDetail mutates the shared Status prototype StatusInternal`
+	*p = status.Status{}  // want `assignment overwrites the shared Status prototype StatusInternal`
+
+	derived := &status.StatusNotFound
+	derived = derived.WithDescription("derived")
+	derived.AddDetail("k", "v")
+
+	status.StatusNotFound.WithDescription("not found").AddDetail("k", "v")
+	status.NewWithCode(5).AddDetail("k", "v")
+}
+
+func descriptions() {
+	_ = status.StatusNotFound.WithDescription("")
+	_ = status.StatusNotFound.WithDescription("").Err()                  // want `status is given an empty description`
+	_ = status.StatusNotFound.WithDescription(empty).Err()               // want `status is given an empty description`
+	_ = operr.NewWithStatus(*status.StatusNotFound.WithDescription(" ")) // want `status is given an empty description`
+	_ = status.StatusNotFound.WithDescriptionf("", 1).Err()
+	_ = status.StatusInternal.WithDescription("name is required") // want `StatusInternal is used for what looks like a validation error`
+	_ = status.StatusInternal.WithDescription("disk on fire")
+}
+
+func argSlices(theCase status.Case, args []any) {
+	_ = status.StatusNotFound.WithDescriptionf("%v %v", args)          // want `argument slice is formatted as a single argument`
+	_ = status.StatusNotFound.WithCaseAndDescf(theCase, "%v %v", args) // want `argument slice is formatted as a single argument`
+	_ = operr.Wrapf(status.StatusNotFound, "%v %v", args)              // want `argument slice is formatted as a single argument`
+	_ = status.StatusNotFound.WithDescriptionf("%v %v", args...)
+	_ = status.StatusNotFound.WithDescriptionf("%v", []string{"a"})
+}
+
+func causes() error {
+	if _, err := os.Open("f"); err != nil {
+		return operr.NewWithStatus(status.StatusNotFound) // want `OpError is built without the error that caused it`
+	}
+	return operr.NewWithStatus(status.StatusNotFound)
+}
@@ -0,0 +1,15 @@
+package fix
+
+import (
+	"os"
+
+	. "github.com/ikonglong/op-status"
+	operr "github.com/ikonglong/op-status/error"
+)
+
+func f(args []any) error {
+	if _, err := os.Open("f"); err != nil {
+		return operr.NewWithStatus(StatusNotFound) // want `OpError is built without the error that caused it`
+	}
+	return StatusNotFound.WithDescriptionf("%v %v", args).Err() // want `argument slice is formatted as a single argument`
+}
@@ -0,0 +1,12 @@
+// Package error is a stub of the op-status error package for the tests of the analyzer.
+package error
+
+import "github.com/ikonglong/op-status"
+
+type OpError struct{}
+
+func (e *OpError) Error() string { return "" }
+
+func NewWithStatus(status opstatus.Status) *OpError                      { return nil }
+func NewWithStatusAndCause(status opstatus.Status, cause error) *OpError { return nil }
+func Wrapf(status opstatus.Status, format string, args ...any) *OpError  { return nil }
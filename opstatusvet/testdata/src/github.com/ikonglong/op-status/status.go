@@ -0,0 +1,22 @@
+// Package opstatus is a stub of the op-status package for the tests of the analyzer.
+package opstatus
+
+type Case interface{ Identifier() string }
+
+type Status struct {
+	details map[string]any
+}
+
+var (
+	StatusNotFound = Status{}
+	StatusInternal = Status{}
+)
+
+func (s *Status) AddDetail(key string, value any)                                    {}
+func (s *Status) AddDetails(details map[string]any)                                  {}
+func (s *Status) WithDescription(description string) *Status                         { return s }
+func (s *Status) WithDescriptionf(descFmt string, fmtArgs ...any) *Status            { return s }
+func (s *Status) WithCaseAndDescf(theCase Case, descFmt string, args ...any) *Status { return s }
+func (s *Status) Err() error                                                         { return nil }
+
+func NewWithCode(code int) *Status { return &Status{} }
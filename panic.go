@@ -0,0 +1,72 @@
+package opstatus
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	panicMappingsMu sync.RWMutex
+	panicMappings   = map[reflect.Type]func(value any) *Status{}
+	// panicTypes are the types of panicMappings in registration order.
+	panicTypes []reflect.Type
+)
+
+// RegisterPanicMapping makes StatusFromPanic convert panic values of type T with given function,
+// e.g., for a domain Violation value used with panic-based control flow in parsers. T may be an
+// interface, e.g., error, in which case the values of the types implementing it are converted too.
+// A later registration for the same type replaces the earlier one. It returns ErrFrozen once the
+// configuration is frozen.
+func RegisterPanicMapping[T any](toStatus func(value T) *Status) error {
+	if err := checkNotFrozen(); err != nil {
+		return err
+	}
+	panicType := reflect.TypeOf((*T)(nil)).Elem()
+	panicMappingsMu.Lock()
+	defer panicMappingsMu.Unlock()
+	if _, found := panicMappings[panicType]; !found {
+		panicTypes = append(panicTypes, panicType)
+	}
+	panicMappings[panicType] = func(value any) *Status {
+		return toStatus(value.(T))
+	}
+	return nil
+}
+
+// StatusFromPanic converts a value recovered from a panic to a status. Values of a type registered
+// with RegisterPanicMapping are converted by the registered function, otherwise the ones assignable
+// to a registered type by the function of the first such type registered. Errors carrying an
+// OpError yield its status, and anything else becomes Internal.
+func StatusFromPanic(value any) *Status {
+	if value == nil {
+		return nil
+	}
+	if toStatus, found := panicMapping(reflect.TypeOf(value)); found {
+		return toStatus(value)
+	}
+	if err, ok := value.(error); ok {
+		var opErr *OpError
+		if errors.As(err, &opErr) && opErr != nil {
+			return opErr.Status()
+		}
+		return StatusInternal.WithDescriptionf("panic: %v", err).WithCause(err)
+	}
+	return StatusInternal.WithDescription(fmt.Sprintf("panic: %v", value))
+}
+
+// panicMapping returns the function converting the panic values of given type.
+func panicMapping(valueType reflect.Type) (func(value any) *Status, bool) {
+	panicMappingsMu.RLock()
+	defer panicMappingsMu.RUnlock()
+	if toStatus, found := panicMappings[valueType]; found {
+		return toStatus, true
+	}
+	for _, panicType := range panicTypes {
+		if valueType.AssignableTo(panicType) {
+			return panicMappings[panicType], true
+		}
+	}
+	return nil, false
+}
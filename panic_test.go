@@ -0,0 +1,40 @@
+package opstatus
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type panicTestViolation struct{ reason string }
+
+func TestStatusFromPanicUsesTheMappingOfAnImplementedInterface(t *testing.T) {
+	t.Cleanup(func() {
+		panicMappingsMu.Lock()
+		defer panicMappingsMu.Unlock()
+		for _, panicType := range []reflect.Type{reflect.TypeOf((*error)(nil)).Elem(), reflect.TypeOf(panicTestViolation{})} {
+			delete(panicMappings, panicType)
+		}
+		panicTypes = nil
+	})
+	if err := RegisterPanicMapping(func(err error) *Status {
+		return StatusUnavailable.WithDescription(err.Error())
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterPanicMapping(func(v panicTestViolation) *Status {
+		return StatusInvalidArgument.WithDescription(v.reason)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := StatusFromPanic(errors.New("connection reset")); got.Code() != CodeUnavailable || got.Description() != "connection reset" {
+		t.Errorf("StatusFromPanic(error) = %v %q, want Unavailable", got.Code(), got.Description())
+	}
+	if got := StatusFromPanic(panicTestViolation{"bad token"}); got.Code() != CodeInvalidArgument {
+		t.Errorf("StatusFromPanic(violation) = %v, want InvalidArgument", got.Code())
+	}
+	if got := StatusFromPanic("boom"); got.Code() != CodeInternal {
+		t.Errorf("StatusFromPanic(string) = %v, want Internal", got.Code())
+	}
+}
@@ -0,0 +1,102 @@
+package opstatus
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	ansiReset = "\033[0m"
+	ansiBold  = "\033[1m"
+	ansiRed   = "\033[31m"
+	ansiGreen = "\033[32m"
+)
+
+// PrettyOptions configures Status.PrettyString.
+type PrettyOptions struct {
+	// Color enables ANSI colors, for terminals.
+	Color bool
+	// Indent is the indentation of nested details. It defaults to two spaces.
+	Indent string
+}
+
+// PrettyString renders this status for humans, e.g., in a terminal: one line per field, with the
+// details as a tree and the retry advice. Use ToErrorCondition for compact log lines.
+func (s *Status) PrettyString(opts PrettyOptions) string {
+	if opts.Indent == "" {
+		opts.Indent = "  "
+	}
+	var b strings.Builder
+	label := func(name string) {
+		if opts.Color {
+			b.WriteString(ansiBold + name + ansiReset)
+		} else {
+			b.WriteString(name)
+		}
+	}
+
+	label("Code: ")
+	code := s.code.String()
+	if opts.Color {
+		color := ansiRed
+		if s.IsOK() {
+			color = ansiGreen
+		}
+		code = color + code + ansiReset
+	}
+	b.WriteString(code + "\n")
+	if s.theCase != nil {
+		label("Case: ")
+		b.WriteString(s.theCase.Identifier() + "\n")
+	}
+	if s.description != "" {
+		label("Description: ")
+		b.WriteString(s.description + "\n")
+	}
+	if len(s.details) > 0 {
+		label("Details:")
+		b.WriteString("\n")
+		writeDetailTree(&b, s.details, opts.Indent, 1)
+	}
+	if !s.IsOK() {
+		label("Retry advice: ")
+		b.WriteString(string(s.RetryAdvice()) + "\n")
+	}
+	if s.cause != nil {
+		label("Cause: ")
+		b.WriteString(s.cause.Error() + "\n")
+	}
+	return b.String()
+}
+
+func writeDetailTree(b *strings.Builder, details map[string]any, indent string, depth int) {
+	keys := make([]string, 0, len(details))
+	for key := range details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	prefix := strings.Repeat(indent, depth)
+	for _, key := range keys {
+		writeDetailValue(b, prefix+key, details[key], indent, depth)
+	}
+}
+
+func writeDetailValue(b *strings.Builder, label string, value any, indent string, depth int) {
+	if nested, ok := value.(map[string]any); ok {
+		b.WriteString(label + ":\n")
+		writeDetailTree(b, nested, indent, depth+1)
+		return
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		b.WriteString(label + ":\n")
+		prefix := strings.Repeat(indent, depth+1)
+		for i := 0; i < v.Len(); i++ {
+			writeDetailValue(b, fmt.Sprintf("%s[%d]", prefix, i), v.Index(i).Interface(), indent, depth+1)
+		}
+		return
+	}
+	fmt.Fprintf(b, "%s: %+v\n", label, value)
+}
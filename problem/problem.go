@@ -0,0 +1,219 @@
+// Package problem renders statuses as RFC 9457 Problem Details documents (application/problem+json)
+// and parses such documents back into statuses.
+package problem
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ikonglong/op-status"
+)
+
+// ContentType is the media type of Problem Details documents.
+const ContentType = "application/problem+json"
+
+// The extension members a status is rendered with besides its details.
+const (
+	MemberCode = "code"
+	MemberCase = "case"
+)
+
+// reservedMemberPrefix replaces opstatus.ReservedDetailKeyPrefix in the names of the extension
+// members of the reserved details, since RFC 9457 recommends names made of letters, digits and
+// underscores, e.g., opstatus.io/field_violations is rendered as opstatus_field_violations.
+const reservedMemberPrefix = "opstatus_"
+
+// escapedMemberPrefix prefixes the names of the extension members of the details whose keys would
+// otherwise collide with the standard members, code, case or the reserved details, e.g., a code
+// detail is rendered as opstatus__code. Reserved detail keys never start with an underscore.
+const escapedMemberPrefix = reservedMemberPrefix + "_"
+
+func memberName(detailKey string) string {
+	switch {
+	case strings.HasPrefix(detailKey, opstatus.ReservedDetailKeyPrefix):
+		return reservedMemberPrefix + strings.TrimPrefix(detailKey, opstatus.ReservedDetailKeyPrefix)
+	case standardMembers[detailKey] || detailKey == MemberCode || detailKey == MemberCase ||
+		strings.HasPrefix(detailKey, reservedMemberPrefix):
+		return escapedMemberPrefix + detailKey
+	}
+	return detailKey
+}
+
+// detailKey returns the key of the detail rendered as the member with given name, which isn't a
+// standard member, code or case.
+func detailKey(memberName string) string {
+	switch {
+	case strings.HasPrefix(memberName, escapedMemberPrefix):
+		return strings.TrimPrefix(memberName, escapedMemberPrefix)
+	case strings.HasPrefix(memberName, reservedMemberPrefix):
+		return opstatus.ReservedDetailKeyPrefix + strings.TrimPrefix(memberName, reservedMemberPrefix)
+	}
+	return memberName
+}
+
+// standardMembers are the members defined by RFC 9457.
+var standardMembers = map[string]bool{
+	"type":     true,
+	"title":    true,
+	"status":   true,
+	"detail":   true,
+	"instance": true,
+}
+
+// Document is a Problem Details document.
+type Document struct {
+	Type     string
+	Title    string
+	Status   int
+	Detail   string
+	Instance string
+	// Extensions are the extension members of the document.
+	Extensions map[string]any
+}
+
+// MarshalJSON encodes this document as a JSON object. It fails if an extension member is named like
+// a standard member, which it would silently replace.
+func (d Document) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(d.Extensions)+5)
+	for name, value := range d.Extensions {
+		if standardMembers[name] {
+			return nil, fmt.Errorf("problem document: extension member %q collides with a standard member", name)
+		}
+		members[name] = value
+	}
+	members["type"] = d.Type
+	members["title"] = d.Title
+	members["status"] = d.Status
+	if d.Detail != "" {
+		members["detail"] = d.Detail
+	}
+	if d.Instance != "" {
+		members["instance"] = d.Instance
+	}
+	return json.Marshal(members)
+}
+
+// Options customizes the rendering of statuses.
+type Options struct {
+	// TypeBaseURI is the URI the case identifiers are appended to in order to form the problem
+	// type, e.g., "https://errors.example.com/". Statuses without a case, or all statuses if empty,
+	// get the "about:blank" type.
+	TypeBaseURI string
+}
+
+// FromStatus returns the Problem Details document of given status. Its details become extension
+// members, except the ones marked sensitive with opstatus.RedactDetails, which are redacted. The
+// reserved details are renamed under the opstatus_ prefix, and the details named like a standard
+// member, code, case or starting with opstatus_ are escaped under the opstatus__ prefix, so that
+// Parse gets them all back.
+func FromStatus(s *opstatus.Status, opts Options) Document {
+	doc := Document{
+		Type:   "about:blank",
+		Title:  http.StatusText(s.HTTPStatus()),
+		Status: s.HTTPStatus(),
+		Detail: s.Description(),
+		Extensions: map[string]any{
+			MemberCode: s.Code().Name(),
+		},
+	}
+	if theCase := s.TheCase(); theCase != nil {
+		doc.Extensions[MemberCase] = theCase.Identifier()
+		if encoded, err := opstatus.EncodeCase(theCase); err == nil {
+			doc.Extensions[MemberCase] = json.RawMessage(encoded)
+		}
+		if opts.TypeBaseURI != "" {
+			doc.Type = opts.TypeBaseURI + theCase.Identifier()
+			doc.Title = s.Code().Name()
+		}
+	}
+	for key, value := range s.Redacted().Details() {
+		if key != opstatus.DetailKeyHTTPStatus {
+			doc.Extensions[memberName(key)] = value
+		}
+	}
+	return doc
+}
+
+// Write writes given status to the response as a Problem Details document.
+func Write(w http.ResponseWriter, s *opstatus.Status, opts Options) error {
+	doc := FromStatus(s, opts)
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(doc.Status)
+	_, err = w.Write(body)
+	return err
+}
+
+// Parse returns the status of given Problem Details document. The code is read from the code
+// extension member if known, otherwise derived from the HTTP status. An HTTP status different from
+// the one the code is mapped to is kept as an opstatus.DetailKeyHTTPStatus override. The extension
+// members other than code and case become details: the ones under the opstatus__ prefix are
+// unescaped, and the other ones under the opstatus_ prefix are decoded into the types of the
+// reserved details, e.g., the ForeignCode.
+func Parse(data []byte) (*opstatus.Status, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, fmt.Errorf("decode problem document: %w", err)
+	}
+	var (
+		httpStatus int
+		detail     string
+		codeName   string
+	)
+	if err := decodeMember(members, "status", &httpStatus); err != nil {
+		return nil, err
+	}
+	if err := decodeMember(members, "detail", &detail); err != nil {
+		return nil, err
+	}
+	if err := decodeMember(members, MemberCode, &codeName); err != nil {
+		return nil, err
+	}
+	code, found := opstatus.CodeByName(codeName)
+	if !found {
+		code = opstatus.NewByHTTPStatus(httpStatus).Code()
+	}
+
+	details := map[string]json.RawMessage{}
+	for name, value := range members {
+		if !standardMembers[name] && name != MemberCode && name != MemberCase {
+			details[detailKey(name)] = value
+		}
+	}
+	if httpStatus != 0 && httpStatus != code.HTTPStatus() {
+		details[opstatus.DetailKeyHTTPStatus] = members["status"]
+	}
+
+	// Decode through the JSON encoding of Status, so that the reserved details get their types.
+	encoded, err := json.Marshal(map[string]any{
+		"code":        code.Value(),
+		"code_name":   code.Name(),
+		"case":        members[MemberCase],
+		"description": strings.TrimSpace(detail),
+		"details":     details,
+	})
+	if err != nil {
+		return nil, err
+	}
+	status := &opstatus.Status{}
+	if err := json.Unmarshal(encoded, status); err != nil {
+		return nil, fmt.Errorf("decode problem document: %w", err)
+	}
+	return status, nil
+}
+
+func decodeMember(members map[string]json.RawMessage, name string, value any) error {
+	data, found := members[name]
+	if !found {
+		return nil
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("decode problem member %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package problem
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/ikonglong/op-status"
+)
+
+// memberNamePattern is the extension member name syntax recommended by RFC 9457, section 3.2.
+var memberNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{2,}$`)
+
+func TestRoundTrip(t *testing.T) {
+	badRequest := opstatus.NewBadRequest(opstatus.FieldViolation{Field: "/quantity", Description: "must be positive"}).
+		WithCase(opstatus.CaseID("invalid_order"))
+	badRequest.AddDetail("order_id", "o-1")
+
+	tests := []struct {
+		name   string
+		status *opstatus.Status
+	}{
+		{"field violations", badRequest},
+		{"HTTP override", opstatus.NewPayloadTooLarge(10, 20)},
+		{"foreign code", opstatus.NewWithForeignCode(42, "Future")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(FromStatus(tt.status, Options{}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var members map[string]any
+			if err := json.Unmarshal(data, &members); err != nil {
+				t.Fatal(err)
+			}
+			for name := range members {
+				if !memberNamePattern.MatchString(name) {
+					t.Errorf("member name %q is not RFC 9457 conformant", name)
+				}
+			}
+
+			parsed, err := Parse(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed.Code() != tt.status.Code() || parsed.Description() != tt.status.Description() ||
+				!opstatus.CaseEqual(parsed.TheCase(), tt.status.TheCase()) {
+				t.Errorf("Parse() = %v %q %v, want %v %q %v", parsed.Code(), parsed.Description(), parsed.TheCase(),
+					tt.status.Code(), tt.status.Description(), tt.status.TheCase())
+			}
+			if !reflect.DeepEqual(parsed.Details(), tt.status.Details()) {
+				t.Errorf("details = %#v, want %#v", parsed.Details(), tt.status.Details())
+			}
+		})
+	}
+}
+
+func TestParseKeepsTheForeignCode(t *testing.T) {
+	data, err := json.Marshal(FromStatus(opstatus.NewWithForeignCode(42, "Future"), Options{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if foreign, found := parsed.ForeignCode(); !found || foreign != (opstatus.ForeignCode{Value: 42, Name: "Future"}) {
+		t.Errorf("ForeignCode() = %+v, %v, want 42 Future", foreign, found)
+	}
+}
+
+func TestRoundTripOfCollidingDetailKeys(t *testing.T) {
+	status := opstatus.StatusFailedPrecondition.WithDescription("order is closed")
+	want := map[string]any{
+		"code":             "ORD-17",
+		"case":             "closed",
+		"type":             "order",
+		"status":           "closed",
+		"detail":           "closed yesterday",
+		"instance":         "o-1",
+		"title":            "Closed",
+		"opstatus_team":    "orders",
+		"opstatus__escape": "kept",
+	}
+	for key, value := range want {
+		status.AddDetail(key, value)
+	}
+
+	data, err := json.Marshal(FromStatus(status, Options{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Code() != opstatus.CodeFailedPrecondition {
+		t.Errorf("Code() = %v, want FailedPrecondition", parsed.Code())
+	}
+	if !reflect.DeepEqual(parsed.Details(), want) {
+		t.Errorf("details = %#v, want %#v", parsed.Details(), want)
+	}
+}
+
+func TestMarshalRejectsExtensionsNamedLikeStandardMembers(t *testing.T) {
+	doc := Document{Type: "about:blank", Status: 400, Extensions: map[string]any{"title": "shadowed"}}
+	if _, err := json.Marshal(doc); err == nil {
+		t.Error("Marshal() succeeded, want an error")
+	}
+}
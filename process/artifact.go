@@ -0,0 +1,72 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ikonglong/op-status"
+)
+
+// EnvArtifact is the environment variable giving the path of the status artifact of a pipeline
+// step: the step writes its status there, e.g., with WriteArtifactToEnv, and the next steps, or
+// the opstatus command, read it back to branch on it.
+const EnvArtifact = "OPSTATUS_ARTIFACT"
+
+// WriteArtifact writes the JSON encoding of given status to given file. The file is replaced
+// atomically, so that a reader never sees a partial status.
+func WriteArtifact(path string, status *opstatus.Status) error {
+	encoded, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("encode status artifact: %w", err)
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("write status artifact: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(append(encoded, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("write status artifact: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("write status artifact: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("write status artifact: %w", err)
+	}
+	return nil
+}
+
+// ReadArtifact reads a status written by WriteArtifact.
+func ReadArtifact(path string) (*opstatus.Status, error) {
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read status artifact: %w", err)
+	}
+	status := &opstatus.Status{}
+	if err := json.Unmarshal(encoded, status); err != nil {
+		return nil, fmt.Errorf("decode status artifact %s: %w", path, err)
+	}
+	return status, nil
+}
+
+// WriteArtifactToEnv writes given status to the artifact named by EnvArtifact, if set.
+func WriteArtifactToEnv(status *opstatus.Status) error {
+	if path := os.Getenv(EnvArtifact); path != "" {
+		return WriteArtifact(path, status)
+	}
+	return nil
+}
+
+// ReadArtifactFromEnv reads the status of the artifact named by EnvArtifact. It returns false if the
+// variable isn't set.
+func ReadArtifactFromEnv() (*opstatus.Status, bool, error) {
+	path := os.Getenv(EnvArtifact)
+	if path == "" {
+		return nil, false, nil
+	}
+	status, err := ReadArtifact(path)
+	return status, err == nil, err
+}
@@ -0,0 +1,28 @@
+package process
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ikonglong/op-status"
+)
+
+func TestArtifactRoundTripThroughTheEnvironment(t *testing.T) {
+	t.Setenv(EnvArtifact, filepath.Join(t.TempDir(), "status.json"))
+	status := opstatus.NewBadRequest(opstatus.FieldViolation{Field: "/input", Description: "is missing"})
+	if err := WriteArtifactToEnv(status); err != nil {
+		t.Fatal(err)
+	}
+	read, found, err := ReadArtifactFromEnv()
+	if err != nil || !found {
+		t.Fatalf("ReadArtifactFromEnv() = %v, %v", found, err)
+	}
+	if read.Code() != opstatus.CodeInvalidArgument || len(read.FieldViolations()) != 1 {
+		t.Errorf("read %v with %v, want the written status", read.Code(), read.FieldViolations())
+	}
+
+	t.Setenv(EnvArtifact, "")
+	if _, found, err := ReadArtifactFromEnv(); found || err != nil {
+		t.Errorf("ReadArtifactFromEnv() without artifact = %v, %v, want false", found, err)
+	}
+}
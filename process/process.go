@@ -0,0 +1,92 @@
+// Package process translates the outcome of child processes, e.g., of batch jobs, into operation
+// statuses.
+package process
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os/exec"
+	"syscall"
+
+	"github.com/ikonglong/op-status"
+)
+
+// Detail keys of the process metadata recorded by FromError.
+const (
+	DetailKeyPID      = "pid"
+	DetailKeyExitCode = "exit_code"
+	DetailKeySignal   = "signal"
+)
+
+// Options configures FromError.
+type Options struct {
+	// Draining tells that the runner is shutting down, so that a process terminated by SIGTERM or
+	// SIGINT was cancelled on purpose.
+	Draining bool
+	// ExitCodes maps the exit codes documented by the program to codes. Unmapped non-zero exit codes
+	// yield Unknown.
+	ExitCodes map[int]opstatus.Code
+}
+
+type signaler interface {
+	Signaled() bool
+	Signal() syscall.Signal
+}
+
+// FromError returns the status for an error returned by exec.Cmd's Run, Wait or Output:
+//
+//   - nil yields OK
+//   - an expired or cancelled context yields DeadlineExceeded or Cancelled
+//   - a missing executable yields NotFound
+//   - a process killed by SIGKILL yields ResourceExhausted, the usual culprit being the OOM killer
+//   - a process terminated by SIGTERM or SIGINT while draining yields Cancelled
+//   - a process killed by another signal yields Internal
+//   - a non-zero exit code yields the code mapped in the options, or Unknown
+//
+// Anything else yields Unknown. The pid, exit code and signal are recorded as details, and given
+// error as the cause.
+func FromError(err error, opts Options) *opstatus.Status {
+	if err == nil {
+		return opstatus.StatusOK.WithDescription("")
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return opstatus.StatusDeadlineExceeded.WithDescription(err.Error()).WithCause(err)
+	case errors.Is(err, context.Canceled):
+		return opstatus.StatusCancelled.WithDescription(err.Error()).WithCause(err)
+	case errors.Is(err, exec.ErrNotFound), errors.Is(err, fs.ErrNotExist):
+		return opstatus.StatusNotFound.WithDescription(err.Error()).WithCause(err)
+	case !errors.As(err, &exitErr):
+		return opstatus.StatusUnknown.WithDescription(err.Error()).WithCause(err)
+	}
+
+	state := exitErr.ProcessState
+	details := []any{DetailKeyPID, state.Pid()}
+	if ws, ok := state.Sys().(signaler); ok && ws.Signaled() {
+		sig := ws.Signal()
+		details = append(details, DetailKeySignal, sig.String())
+		var status *opstatus.Status
+		switch {
+		case sig == syscall.SIGKILL:
+			status = opstatus.StatusResourceExhausted.WithDescriptionf("process killed by %v, likely out of memory", sig)
+		case (sig == syscall.SIGTERM || sig == syscall.SIGINT) && opts.Draining:
+			status = opstatus.StatusCancelled.WithDescriptionf("process terminated by %v during drain", sig)
+		case sig == syscall.SIGTERM || sig == syscall.SIGINT:
+			status = opstatus.StatusUnknown.WithDescriptionf("process terminated by %v", sig)
+		default:
+			status = opstatus.StatusInternal.WithDescriptionf("process killed by %v", sig)
+		}
+		return status.WithDetails(details...).WithCause(err)
+	}
+
+	exitCode := state.ExitCode()
+	details = append(details, DetailKeyExitCode, exitCode)
+	code, found := opts.ExitCodes[exitCode]
+	if !found {
+		code = opstatus.CodeUnknown
+	}
+	return opstatus.NewWithCode(code).WithDescriptionf("process exited with code %d", exitCode).
+		WithDetails(details...).WithCause(err)
+}
@@ -0,0 +1,27 @@
+package opstatus
+
+// DetailKeyRangeInfo is the detail key of the RangeInfo attached by OutOfRangef.
+const DetailKeyRangeInfo = ReservedDetailKeyPrefix + "range_info"
+
+// RangeInfo tells the valid bounds of a value found out of range, so that clients can clamp to them.
+type RangeInfo struct {
+	// Field is the name or path of the field holding the value, e.g., "page_token" or "offset".
+	Field     string `json:"field"`
+	Requested int64  `json:"requested"`
+	Min       int64  `json:"min"`
+	Max       int64  `json:"max"`
+}
+
+// OutOfRangef returns an OutOfRange status carrying given RangeInfo, with the formatted
+// description.
+func OutOfRangef(info RangeInfo, descFmt string, fmtArgs ...any) *Status {
+	status := StatusOutOfRange.WithDescriptionf(descFmt, fmtArgs...)
+	status.setDetail(DetailKeyRangeInfo, info)
+	return status
+}
+
+// RangeInfo returns the RangeInfo attached to this status, if any.
+func (s *Status) RangeInfo() (RangeInfo, bool) {
+	info, found := s.details[DetailKeyRangeInfo].(RangeInfo)
+	return info, found
+}
@@ -0,0 +1,175 @@
+package opstatus
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RedactedValue replaces the values of redacted details.
+const RedactedValue = "[REDACTED]"
+
+var (
+	redactedKeysMu sync.RWMutex
+	redactedKeys   = map[string]bool{}
+)
+
+// RedactDetails marks the details with given keys as sensitive, so that Redacted scrubs them. Keys
+// are normalized as configured by SetDetailKeyNormalization. It returns ErrFrozen once the
+// configuration is frozen.
+func RedactDetails(keys ...string) error {
+	if err := checkNotFrozen(); err != nil {
+		return err
+	}
+	redactedKeysMu.Lock()
+	defer redactedKeysMu.Unlock()
+	for _, key := range keys {
+		redactedKeys[NormalizeDetailKey(key)] = true
+	}
+	return nil
+}
+
+// UnredactDetails removes the mark of RedactDetails from the details with given keys, e.g., to
+// restore the configuration at the end of a test. It returns ErrFrozen once the configuration is
+// frozen.
+func UnredactDetails(keys ...string) error {
+	if err := checkNotFrozen(); err != nil {
+		return err
+	}
+	redactedKeysMu.Lock()
+	defer redactedKeysMu.Unlock()
+	for _, key := range keys {
+		delete(redactedKeys, NormalizeDetailKey(key))
+	}
+	return nil
+}
+
+// RedactedDetailKeys returns the keys marked with RedactDetails, sorted.
+func RedactedDetailKeys() []string {
+	redactedKeysMu.RLock()
+	defer redactedKeysMu.RUnlock()
+	keys := make([]string, 0, len(redactedKeys))
+	for key := range redactedKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Redacted returns a scrubbed copy of this status, for statuses sent to third-party systems, e.g.,
+// chat alerts or external ticketing. The values of the details marked with RedactDetails are
+// replaced by RedactedValue, within nested maps and slices of details included, and so are the
+// offending values of the field violations whose field is named like one of them, e.g., a violation
+// of /user/password if password is marked. The maps and slices are copied, so that scrubbing
+// doesn't touch this status, and the cause is dropped since its message is out of control.
+func (s *Status) Redacted() *Status {
+	redacted := s.derive()
+	redacted.cause = nil
+	redactedKeysMu.RLock()
+	defer redactedKeysMu.RUnlock()
+	redacted.details = redactDetails(redacted.details)
+	return redacted
+}
+
+// HasSensitiveDetails tells if this status carries any detail marked with RedactDetails, nested
+// ones and field violations included, i.e., if Redacted would scrub anything from its details.
+func (s *Status) HasSensitiveDetails() bool {
+	redactedKeysMu.RLock()
+	defer redactedKeysMu.RUnlock()
+	return hasSensitiveDetails(s.details)
+}
+
+// hasSensitiveDetails tells if given details hold a sensitive value. The caller must hold the read
+// lock of redactedKeys.
+func hasSensitiveDetails(details map[string]any) bool {
+	for key, value := range details {
+		if redactedKeys[key] || hasSensitiveValue(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasSensitiveValue(value any) bool {
+	switch v := value.(type) {
+	case map[string]any:
+		return hasSensitiveDetails(v)
+	case map[string]string:
+		for key := range v {
+			if redactedKeys[key] {
+				return true
+			}
+		}
+	case []any:
+		for _, element := range v {
+			if hasSensitiveValue(element) {
+				return true
+			}
+		}
+	case []FieldViolation:
+		for _, violation := range v {
+			if violation.Value != nil && isSensitiveField(violation.Field) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactDetails returns a copy of given details with the sensitive values replaced. The caller
+// must hold the read lock of redactedKeys.
+func redactDetails(details map[string]any) map[string]any {
+	redacted := make(map[string]any, len(details))
+	for key, value := range details {
+		if redactedKeys[key] {
+			redacted[key] = RedactedValue
+			continue
+		}
+		redacted[key] = redactValue(value)
+	}
+	return redacted
+}
+
+// redactValue returns a copy of given detail value with the sensitive values replaced, if it is a
+// map or slice of details or field violations, otherwise the value itself.
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return redactDetails(v)
+	case map[string]string:
+		redacted := make(map[string]string, len(v))
+		for key, element := range v {
+			if redactedKeys[key] {
+				element = RedactedValue
+			}
+			redacted[key] = element
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(v))
+		for i, element := range v {
+			redacted[i] = redactValue(element)
+		}
+		return redacted
+	case []FieldViolation:
+		redacted := make([]FieldViolation, len(v))
+		for i, violation := range v {
+			if violation.Value != nil && isSensitiveField(violation.Field) {
+				violation = violation.WithRedactedValue()
+			}
+			redacted[i] = violation
+		}
+		return redacted
+	}
+	return value
+}
+
+// isSensitiveField tells if the last name of given field path, a JSON Pointer or a protobuf field
+// path, is marked with RedactDetails. The caller must hold the read lock of redactedKeys.
+func isSensitiveField(field string) bool {
+	name := field[strings.LastIndexAny(field, "/.")+1:]
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i]
+	}
+	return redactedKeys[NormalizeDetailKey(name)]
+}
@@ -0,0 +1,45 @@
+package opstatus
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedactedScrubsNestedDetailsWithoutTouchingTheOriginal(t *testing.T) {
+	if err := RedactDetails("password", "token"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { UnredactDetails("password", "token") })
+
+	status := NewBadRequest(
+		FieldViolation{Field: "/user/password", Description: "is too short", Value: "hunter2"},
+		FieldViolation{Field: "user.name", Description: "is required", Value: ""},
+	).WithDetails(
+		"attempts", []any{map[string]any{"token": "t-1", "at": "noon"}},
+		"headers", map[string]string{"token": "t-2", "accept": "*/*"},
+	)
+	if !status.HasSensitiveDetails() {
+		t.Error("HasSensitiveDetails() = false, want true")
+	}
+
+	redacted := status.Redacted()
+	violations := redacted.FieldViolations()
+	if violations[0].Value != nil || !violations[0].Redacted || violations[1].Value != "" {
+		t.Errorf("redacted violations = %+v, want the password one redacted only", violations)
+	}
+	attempts, _ := redacted.Detail("attempts")
+	if want := []any{map[string]any{"token": RedactedValue, "at": "noon"}}; !reflect.DeepEqual(attempts, want) {
+		t.Errorf("redacted attempts = %v, want %v", attempts, want)
+	}
+	headers, _ := redacted.Detail("headers")
+	if want := map[string]string{"token": RedactedValue, "accept": "*/*"}; !reflect.DeepEqual(headers, want) {
+		t.Errorf("redacted headers = %v, want %v", headers, want)
+	}
+
+	if status.FieldViolations()[0].Value != "hunter2" {
+		t.Error("Redacted scrubbed the violations of the original status")
+	}
+	if attempts, _ := status.Detail("attempts"); attempts.([]any)[0].(map[string]any)["token"] != "t-1" {
+		t.Error("Redacted scrubbed the attempts of the original status")
+	}
+}
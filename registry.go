@@ -0,0 +1,384 @@
+package opstatus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ikonglong/op-status/http"
+)
+
+// RegisteredCase is a case registered with RegisterCase or LoadRegistry, with the code and default
+// description of its statuses.
+type RegisteredCase struct {
+	identifier         string
+	code               Code
+	defaultDescription string
+	owner              CaseOwner
+	docsURL            string
+	// fromDocument tells if the case was registered by a registry document rather than by code.
+	fromDocument bool
+}
+
+func (c RegisteredCase) Identifier() string {
+	return c.identifier
+}
+
+// Code returns the code of the statuses of this case.
+func (c RegisteredCase) Code() Code {
+	return c.code
+}
+
+// DefaultDescription returns the description given to the statuses of this case by default.
+func (c RegisteredCase) DefaultDescription() string {
+	return c.defaultDescription
+}
+
+// DocsURL returns the link to the documentation of this case given by its registry document, if
+// any.
+func (c RegisteredCase) DocsURL() string {
+	return c.docsURL
+}
+
+// Status returns a status of this case with its code and default description.
+func (c RegisteredCase) Status() *Status {
+	return NewWithCode(c.code).WithCaseAndDesc(c, c.defaultDescription)
+}
+
+var (
+	// caseRegistryMu guards the configuration of the registry documents, the HTTP overrides
+	// excepted, which are published along under it.
+	caseRegistryMu sync.RWMutex
+	caseRegistry   = map[string]RegisteredCase{}
+	// registryRetryPolicies are the retry policies of the registry documents by name.
+	registryRetryPolicies = map[string]RetryPolicy{}
+	// defaultMessages are the default messages of the registry documents by code. They are read
+	// without locking by NewWithCode.
+	defaultMessages atomic.Pointer[map[Code]string]
+)
+
+// DefaultMessage returns the default message declared by the registry documents for given code,
+// which NewWithCode gives the statuses it creates.
+func DefaultMessage(code Code) string {
+	return (*loadDefaultMessages())[code]
+}
+
+func loadDefaultMessages() *map[Code]string {
+	if messages := defaultMessages.Load(); messages != nil {
+		return messages
+	}
+	return &map[Code]string{}
+}
+
+// parseRegistryDuration parses the duration of given field of a retry policy, which is zero if
+// empty, i.e., the default of the field.
+func parseRegistryDuration(problems *MultiStatus, policy, field, value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		problems.Add(StatusInvalidArgument.WithDescriptionf("retry_policies.%s.%s: %v", policy, field, err))
+	}
+	return duration
+}
+
+// RegisterCase registers a case with the code and default description of its statuses. Case
+// identifiers must be unique. It returns ErrFrozen once the configuration is frozen.
+func RegisterCase(identifier string, code Code, defaultDescription string) (RegisteredCase, error) {
+	if err := checkNotFrozen(); err != nil {
+		return RegisteredCase{}, err
+	}
+	registered := RegisteredCase{
+		identifier:         identifier,
+		code:               code,
+		defaultDescription: defaultDescription,
+	}
+	caseRegistryMu.Lock()
+	defer caseRegistryMu.Unlock()
+	if err := checkCase(registered, caseRegistry); err != nil {
+		return RegisteredCase{}, err
+	}
+	caseRegistry[identifier] = registered
+	return registered, nil
+}
+
+// LookupCase returns the registered case with given identifier.
+func LookupCase(identifier string) (RegisteredCase, bool) {
+	caseRegistryMu.RLock()
+	defer caseRegistryMu.RUnlock()
+	registered, found := caseRegistry[identifier]
+	return registered, found
+}
+
+// RegisteredCases returns the registered cases sorted by identifier.
+func RegisteredCases() []RegisteredCase {
+	caseRegistryMu.RLock()
+	defer caseRegistryMu.RUnlock()
+	registered := make([]RegisteredCase, 0, len(caseRegistry))
+	for _, c := range caseRegistry {
+		registered = append(registered, c)
+	}
+	sort.Slice(registered, func(i, j int) bool { return registered[i].identifier < registered[j].identifier })
+	return registered
+}
+
+// checkCase tells why given case can't be added to given registry, if it can't.
+func checkCase(c RegisteredCase, registry map[string]RegisteredCase) error {
+	if c.identifier == "" {
+		return fmt.Errorf("case identifier is empty")
+	}
+	if _, found := CodeByName(c.code.name); !found {
+		return fmt.Errorf("case %s has unknown code %v", c.identifier, c.code)
+	}
+	if _, found := registry[c.identifier]; found {
+		return fmt.Errorf("case %s is already registered", c.identifier)
+	}
+	return nil
+}
+
+// registryConfig is the document read by LoadRegistry.
+type registryConfig struct {
+	// HTTPOverrides maps code names to the HTTP status codes they are mapped to.
+	HTTPOverrides map[string]int `json:"http_overrides"`
+	Cases         []struct {
+		Identifier  string    `json:"identifier"`
+		Code        string    `json:"code"`
+		Description string    `json:"description"`
+		Owner       CaseOwner `json:"owner"`
+		DocsURL     string    `json:"docs_url"`
+	} `json:"cases"`
+	// RetryPolicies maps names to retry policies, whose durations are parsed by time.ParseDuration.
+	RetryPolicies map[string]struct {
+		MaxAttempts    int     `json:"max_attempts"`
+		InitialBackoff string  `json:"initial_backoff"`
+		MaxBackoff     string  `json:"max_backoff"`
+		Multiplier     float64 `json:"multiplier"`
+	} `json:"retry_policies"`
+	// DefaultMessages maps code names to the default descriptions of their statuses.
+	DefaultMessages map[string]string `json:"default_messages"`
+}
+
+// LoadRegistry reads a JSON registry document from given filesystem, typically an embed.FS, e.g.:
+//
+//	{
+//	  "http_overrides": {"FailedPrecondition": 422},
+//	  "cases": [{
+//	    "identifier": "order_not_found", "code": "NotFound", "description": "order not found",
+//	    "owner": {"team": "orders", "contact": "#orders", "runbook_url": "https://runbooks/orders"},
+//	    "docs_url": "https://docs/errors/order_not_found"
+//	  }],
+//	  "retry_policies": {"payments": {"max_attempts": 5, "initial_backoff": "200ms", "max_backoff": "5s"}},
+//	  "default_messages": {"ServiceUnavailable": "The service is temporarily unavailable, try again later."}
+//	}
+//
+// and registers everything it declares atomically: if anything is invalid, nothing is registered,
+// and concurrent readers see the registrations of the document all at once. The retry policies
+// are then available from RetryPolicyByName and the default messages describe the statuses made by
+// NewWithCode.
+// The problems found are returned together as a MultiStatus of InvalidArgument statuses, which is
+// OK on success. Once the configuration is frozen, it holds a FailedPrecondition status caused by
+// ErrFrozen instead.
+func LoadRegistry(fsys fs.FS, path string) *MultiStatus {
+	return applyRegistry(fsys, path, false)
+}
+
+// ReloadRegistry reads a registry document like LoadRegistry does, but replaces the cases, HTTP
+// overrides, retry policies and default messages of the previous documents instead of adding to
+// them, e.g., to let operators change them
+// without redeploying. The cases registered by code, the owners set by SetCaseOwner, the mapping
+// profile and the overrides made by code are kept. The swap is atomic: concurrent conversions see
+// either the old or the new configuration, and nothing changes if the document is invalid.
+func ReloadRegistry(fsys fs.FS, path string) *MultiStatus {
+	return applyRegistry(fsys, path, true)
+}
+
+// ReloadRegistryOnSignal calls ReloadRegistry every time one of given signals, typically SIGHUP,
+// is received, until given context is done. The result of every reload is passed to given function.
+func ReloadRegistryOnSignal(ctx context.Context, fsys fs.FS, path string, onReload func(problems *MultiStatus), signals ...os.Signal) {
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+	go func() {
+		defer signal.Stop(received)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-received:
+				onReload(ReloadRegistry(fsys, path))
+			}
+		}
+	}()
+}
+
+func applyRegistry(fsys fs.FS, path string, replace bool) *MultiStatus {
+	problems := &MultiStatus{}
+	if err := checkNotFrozen(); err != nil {
+		problems.Add(StatusFailedPrecondition.WithDescriptionf("load registry %s: %v", path, err).WithCause(err))
+		return problems
+	}
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		problems.Add(StatusInvalidArgument.WithDescriptionf("read registry %s: %v", path, err).WithCause(err))
+		return problems
+	}
+	var config registryConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		problems.Add(StatusInvalidArgument.WithDescriptionf("decode registry %s: %v", path, err).WithCause(err))
+		return problems
+	}
+
+	overrides := map[Code]http.Status{}
+	for name, statusCode := range config.HTTPOverrides {
+		code, found := CodeByName(name)
+		if !found {
+			problems.Add(StatusInvalidArgument.WithDescriptionf("http_overrides: unknown code %q", name))
+			continue
+		}
+		if !http.IsDefined(statusCode) {
+			problems.Add(StatusInvalidArgument.WithDescriptionf("http_overrides: HTTP status %d of %s is not defined", statusCode, name))
+			continue
+		}
+		overrides[code] = http.Status(statusCode)
+	}
+	messages := map[Code]string{}
+	for name, message := range config.DefaultMessages {
+		code, found := CodeByName(name)
+		if !found {
+			problems.Add(StatusInvalidArgument.WithDescriptionf("default_messages: unknown code %q", name))
+			continue
+		}
+		messages[code] = strings.TrimSpace(message)
+	}
+	policies := map[string]RetryPolicy{}
+	for name, p := range config.RetryPolicies {
+		policy := RetryPolicy{MaxAttempts: p.MaxAttempts, Multiplier: p.Multiplier}
+		policy.InitialBackoff = parseRegistryDuration(problems, name, "initial_backoff", p.InitialBackoff)
+		policy.MaxBackoff = parseRegistryDuration(problems, name, "max_backoff", p.MaxBackoff)
+		policies[name] = policy
+	}
+
+	caseRegistryMu.Lock()
+	defer caseRegistryMu.Unlock()
+	registry := map[string]RegisteredCase{}
+	for id, registered := range caseRegistry {
+		if !replace || !registered.fromDocument {
+			registry[id] = registered
+		}
+	}
+	for i, c := range config.Cases {
+		code, found := CodeByName(c.Code)
+		if !found {
+			problems.Add(StatusInvalidArgument.WithDescriptionf("cases[%d]: unknown code %q", i, c.Code))
+			continue
+		}
+		registered := RegisteredCase{
+			identifier:         c.Identifier,
+			code:               code,
+			defaultDescription: c.Description,
+			owner:              c.Owner,
+			docsURL:            c.DocsURL,
+			fromDocument:       true,
+		}
+		if owner, found := caseOwners[c.Identifier]; found {
+			registered.owner = owner
+		}
+		if err := checkCase(registered, registry); err != nil {
+			problems.Add(StatusInvalidArgument.WithDescriptionf("cases[%d]: %v", i, err))
+			continue
+		}
+		registry[registered.identifier] = registered
+	}
+	if !replace {
+		for name, policy := range registryRetryPolicies {
+			if _, found := policies[name]; found {
+				problems.Add(StatusInvalidArgument.WithDescriptionf("retry_policies: %q is already registered", name))
+				continue
+			}
+			policies[name] = policy
+		}
+		for code, message := range *loadDefaultMessages() {
+			if _, found := messages[code]; found {
+				problems.Add(StatusInvalidArgument.WithDescriptionf("default_messages: %v already has one", code))
+				continue
+			}
+			messages[code] = message
+		}
+	}
+	if !problems.IsOK() {
+		return problems
+	}
+
+	// Everything is published while holding caseRegistryMu, so that the readers holding it see
+	// the HTTP overrides and the rest of the document together.
+	updateHTTPMapping(func() {
+		if replace {
+			registryHTTPOverrides = map[Code]http.Status{}
+		}
+		for code, status := range overrides {
+			registryHTTPOverrides[code] = status
+		}
+		caseRegistry = registry
+		registryRetryPolicies = policies
+		defaultMessages.Store(&messages)
+	})
+	return problems
+}
+
+// ValidateRegistry checks the package-level configuration for problems, e.g., at startup, and
+// returns them together as a MultiStatus of FailedPrecondition statuses, which is OK if there is
+// none. It reports:
+//
+//   - HTTP overrides out of the class of the default mapping, e.g., a client error code mapped to
+//     a 5xx status
+//   - case identifiers that only differ by letter case or surrounding whitespace
+//   - cases of given templates that aren't registered
+//
+// It is safe to call concurrently with registrations.
+func ValidateRegistry(templates ...*Status) *MultiStatus {
+	problems := &MultiStatus{}
+	// The registry documents publish their HTTP overrides and cases together under caseRegistryMu.
+	caseRegistryMu.RLock()
+	defer caseRegistryMu.RUnlock()
+	mapping := currentHTTPMapping()
+	for _, code := range codeList {
+		defaultStatus, overridden := codeToHTTPStatus[code], mapping[code]
+		if defaultStatus/100 != overridden/100 {
+			problems.Add(StatusFailedPrecondition.WithDescriptionf(
+				"%v is mapped to HTTP status %d, out of the class of its default %d", code, overridden, defaultStatus))
+		}
+	}
+
+	identifiers := make([]string, 0, len(caseRegistry))
+	for id := range caseRegistry {
+		identifiers = append(identifiers, id)
+	}
+	sort.Strings(identifiers)
+	normalized := map[string]string{}
+	for _, id := range identifiers {
+		key := strings.ToLower(strings.TrimSpace(id))
+		if other, found := normalized[key]; found {
+			problems.Add(StatusFailedPrecondition.WithDescriptionf("cases %q and %q are duplicates", other, id))
+			continue
+		}
+		normalized[key] = id
+	}
+	for _, template := range templates {
+		if template.theCase == nil {
+			continue
+		}
+		if _, found := caseRegistry[template.theCase.Identifier()]; !found {
+			problems.Add(StatusFailedPrecondition.WithDescriptionf(
+				"template %s refers to unregistered case %q", template.ToErrorCondition(), template.theCase.Identifier()))
+		}
+	}
+	return problems
+}
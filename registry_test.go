@@ -0,0 +1,105 @@
+package opstatus
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestReloadRegistryKeepsProfileAndCodeConfiguration(t *testing.T) {
+	t.Cleanup(func() {
+		UseMappingProfile(ProfileGoogle)
+		ReloadRegistry(fstest.MapFS{"registry.json": {Data: []byte(`{}`)}}, "registry.json")
+	})
+	if err := UseMappingProfile(ProfilePragmaticREST); err != nil {
+		t.Fatal(err)
+	}
+	if err := MapToHTTPStatus(CodeNotFound, 410); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RegisterCase("reload_test_code_case", CodeNotFound, "not found"); err != nil {
+		t.Fatal(err)
+	}
+	owner := CaseOwner{Team: "orders"}
+	if err := SetCaseOwner("reload_test_code_case", owner); err != nil {
+		t.Fatal(err)
+	}
+
+	fsys := fstest.MapFS{
+		"v1.json": {Data: []byte(`{"cases": [{"identifier": "reload_test_doc_case", "code": "NotFound"}]}`)},
+		"v2.json": {Data: []byte(`{"http_overrides": {"OutOfRange": 422}}`)},
+	}
+	if problems := LoadRegistry(fsys, "v1.json"); !problems.IsOK() {
+		t.Fatal(problems.Statuses())
+	}
+	if problems := ReloadRegistry(fsys, "v2.json"); !problems.IsOK() {
+		t.Fatal(problems.Statuses())
+	}
+
+	for code, want := range map[Code]int{
+		CodeFailedPrecondition: 422, // profile
+		CodeAborted:            412, // profile
+		CodeNotFound:           410, // override made by code
+		CodeOutOfRange:         422, // registry document
+	} {
+		if got := code.HTTPStatus(); got != want {
+			t.Errorf("%v.HTTPStatus() = %d, want %d", code, got, want)
+		}
+	}
+	if registered, found := LookupCase("reload_test_code_case"); !found || registered.Owner() != owner {
+		t.Errorf("case registered by code = %+v, %v, want it kept with its owner", registered, found)
+	}
+	if _, found := LookupCase("reload_test_doc_case"); found {
+		t.Errorf("case of the previous document is still registered")
+	}
+}
+
+func TestRegistryDeclaresRetryPoliciesAndDefaultMessages(t *testing.T) {
+	t.Cleanup(func() {
+		ReloadRegistry(fstest.MapFS{"registry.json": {Data: []byte(`{}`)}}, "registry.json")
+	})
+	fsys := fstest.MapFS{
+		"v1.json": {Data: []byte(`{
+			"retry_policies": {"payments": {"max_attempts": 5, "initial_backoff": "200ms", "max_backoff": "5s", "multiplier": 3}},
+			"default_messages": {"ServiceUnavailable": "Try again later."}
+		}`)},
+		"duplicates.json": {Data: []byte(`{
+			"retry_policies": {"payments": {"max_attempts": 2}},
+			"default_messages": {"ServiceUnavailable": "Again."}
+		}`)},
+		"invalid.json": {Data: []byte(`{
+			"retry_policies": {"search": {"initial_backoff": "soon"}},
+			"default_messages": {"Unheard": "?"}
+		}`)},
+	}
+	if problems := LoadRegistry(fsys, "v1.json"); !problems.IsOK() {
+		t.Fatal(problems.Statuses())
+	}
+	want := RetryPolicy{MaxAttempts: 5, InitialBackoff: 200 * time.Millisecond, MaxBackoff: 5 * time.Second, Multiplier: 3}
+	if policy, found := RetryPolicyByName("payments"); !found || policy != want {
+		t.Errorf("RetryPolicyByName() = %+v, %v, want %+v", policy, found, want)
+	}
+	if got := NewWithCode(CodeUnavailable).Description(); got != "Try again later." {
+		t.Errorf("NewWithCode().Description() = %q, want the default message", got)
+	}
+	if got := StatusUnavailable.Description(); got != "" {
+		t.Errorf("the prototype got the default message %q", got)
+	}
+
+	if problems := LoadRegistry(fsys, "duplicates.json"); len(problems.Statuses()) != 2 {
+		t.Errorf("LoadRegistry() of duplicates = %v, want 2 problems", problems.Statuses())
+	}
+	if problems := LoadRegistry(fsys, "invalid.json"); len(problems.Statuses()) != 2 {
+		t.Errorf("LoadRegistry() of invalid declarations = %v, want 2 problems", problems.Statuses())
+	}
+	if policy, _ := RetryPolicyByName("payments"); policy != want {
+		t.Errorf("failed loads changed the policy to %+v", policy)
+	}
+
+	if problems := ReloadRegistry(fsys, "duplicates.json"); !problems.IsOK() {
+		t.Fatal(problems.Statuses())
+	}
+	if got := NewWithCodeValue(CodeUnavailable.Value()).Description(); got != "Again." {
+		t.Errorf("NewWithCodeValue().Description() = %q after reloading", got)
+	}
+}
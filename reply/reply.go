@@ -0,0 +1,167 @@
+// Package reply maps the numeric reply codes of classic protocols, SMTP (RFC 5321) and FTP
+// (RFC 959), to operation statuses and back, so that mail and file-transfer adapters classify
+// transient and permanent failures consistently with the rest of the system.
+package reply
+
+import (
+	"github.com/ikonglong/op-status"
+)
+
+// DetailKeyReplyCode is the detail key under which the original reply code is recorded.
+const DetailKeyReplyCode = "reply_code"
+
+var smtpToCode = map[int]opstatus.Code{
+	421: opstatus.CodeUnavailable,
+	450: opstatus.CodeUnavailable,
+	451: opstatus.CodeUnavailable,
+	452: opstatus.CodeResourceExhausted,
+	455: opstatus.CodeUnavailable,
+	500: opstatus.CodeInvalidArgument,
+	501: opstatus.CodeInvalidArgument,
+	502: opstatus.CodeUnimplemented,
+	503: opstatus.CodeFailedPrecondition,
+	504: opstatus.CodeUnimplemented,
+	530: opstatus.CodeUnauthenticated,
+	535: opstatus.CodeUnauthenticated,
+	550: opstatus.CodeNotFound,
+	551: opstatus.CodeNotFound,
+	552: opstatus.CodeResourceExhausted,
+	553: opstatus.CodeInvalidArgument,
+	554: opstatus.CodeFailedPrecondition,
+	555: opstatus.CodeInvalidArgument,
+}
+
+var ftpToCode = map[int]opstatus.Code{
+	421: opstatus.CodeUnavailable,
+	425: opstatus.CodeUnavailable,
+	426: opstatus.CodeUnavailable,
+	450: opstatus.CodeUnavailable,
+	451: opstatus.CodeUnavailable,
+	452: opstatus.CodeResourceExhausted,
+	500: opstatus.CodeInvalidArgument,
+	501: opstatus.CodeInvalidArgument,
+	502: opstatus.CodeUnimplemented,
+	503: opstatus.CodeFailedPrecondition,
+	504: opstatus.CodeUnimplemented,
+	530: opstatus.CodeUnauthenticated,
+	532: opstatus.CodeUnauthenticated,
+	550: opstatus.CodeNotFound,
+	551: opstatus.CodeInvalidArgument,
+	552: opstatus.CodeResourceExhausted,
+	553: opstatus.CodeInvalidArgument,
+}
+
+var codeToSMTP = map[opstatus.Code]int{
+	opstatus.CodeOK:                 250,
+	opstatus.CodeUnavailable:        421,
+	opstatus.CodeResourceExhausted:  452,
+	opstatus.CodeInvalidArgument:    501,
+	opstatus.CodeUnimplemented:      502,
+	opstatus.CodeFailedPrecondition: 554,
+	opstatus.CodeUnauthenticated:    530,
+	opstatus.CodePermissionDenied:   550,
+	opstatus.CodeNotFound:           550,
+}
+
+var codeToFTP = map[opstatus.Code]int{
+	opstatus.CodeOK:                 200,
+	opstatus.CodeUnavailable:        421,
+	opstatus.CodeResourceExhausted:  452,
+	opstatus.CodeInvalidArgument:    501,
+	opstatus.CodeUnimplemented:      502,
+	opstatus.CodeFailedPrecondition: 503,
+	opstatus.CodeUnauthenticated:    530,
+	opstatus.CodePermissionDenied:   550,
+	opstatus.CodeNotFound:           550,
+}
+
+// FromSMTP returns the status for given SMTP reply code and text.
+func FromSMTP(replyCode int, text string) *opstatus.Status {
+	return fromReply(smtpToCode, replyCode, text)
+}
+
+// FromFTP returns the status for given FTP reply code and text.
+func FromFTP(replyCode int, text string) *opstatus.Status {
+	return fromReply(ftpToCode, replyCode, text)
+}
+
+// ToSMTP returns the SMTP reply code for given status: the recorded one if any, e.g., by FromSMTP,
+// otherwise the one of its code if of the class of its transience, otherwise 451 if transient and
+// 554 if permanent.
+func ToSMTP(status *opstatus.Status) int {
+	return toReply(codeToSMTP, status, 451, 554)
+}
+
+// ToFTP returns the FTP reply code for given status: the recorded one if any, e.g., by FromFTP,
+// otherwise the one of its code if of the class of its transience, otherwise 451 if transient and
+// 550 if permanent.
+func ToFTP(status *opstatus.Status) int {
+	return toReply(codeToFTP, status, 451, 550)
+}
+
+// fromReply maps a reply code: positive completion and intermediate replies (1xx to 3xx) are OK,
+// and unmapped transient (4xx) and permanent (5xx) negative replies become Unavailable and
+// FailedPrecondition respectively. The transience of negative replies is the one of their class,
+// whatever their code, e.g., 552 is a permanent ResourceExhausted.
+func fromReply(mapping map[int]opstatus.Code, replyCode int, text string) *opstatus.Status {
+	code, found := mapping[replyCode]
+	if !found {
+		switch replyCode / 100 {
+		case 1, 2, 3:
+			code = opstatus.CodeOK
+		case 4:
+			code = opstatus.CodeUnavailable
+		case 5:
+			code = opstatus.CodeFailedPrecondition
+		default:
+			code = opstatus.CodeUnknown
+		}
+	}
+	status := opstatus.NewWithCode(code).WithDescription(text).WithDetails(DetailKeyReplyCode, replyCode)
+	switch replyCode / 100 {
+	case 4:
+		status = status.WithTransience(opstatus.Transient)
+	case 5:
+		status = status.WithTransience(opstatus.Permanent)
+	}
+	return status
+}
+
+// toReply maps a status to a reply code. Unless the reply code was recorded, the reply class follows
+// the transience of the status, transient (4xx) or permanent (5xx), so that peers don't retry
+// permanent failures forever.
+func toReply(mapping map[opstatus.Code]int, status *opstatus.Status, transient, permanent int) int {
+	if replyCode, found := recordedReplyCode(status); found {
+		return replyCode
+	}
+	if status.IsOK() {
+		return mapping[opstatus.CodeOK]
+	}
+	class, fallback := 5, permanent
+	if status.IsTransient() {
+		class, fallback = 4, transient
+	}
+	if replyCode, found := mapping[status.Code()]; found && replyCode/100 == class {
+		return replyCode
+	}
+	return fallback
+}
+
+// recordedReplyCode returns the reply code recorded in the details of given status, which is a
+// float64 once decoded from JSON.
+func recordedReplyCode(status *opstatus.Status) (int, bool) {
+	value, found := status.Detail(DetailKeyReplyCode)
+	if !found {
+		return 0, false
+	}
+	var replyCode int
+	switch v := value.(type) {
+	case int:
+		replyCode = v
+	case float64:
+		replyCode = int(v)
+	default:
+		return 0, false
+	}
+	return replyCode, replyCode >= 100 && replyCode < 600
+}
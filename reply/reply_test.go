@@ -0,0 +1,65 @@
+package reply
+
+import (
+	"testing"
+
+	"github.com/ikonglong/op-status"
+)
+
+func TestTransienceFollowsTheReplyClass(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        *opstatus.Status
+		wantCode      opstatus.Code
+		wantTransient bool
+	}{
+		{"SMTP 452 insufficient storage", FromSMTP(452, "insufficient system storage"), opstatus.CodeResourceExhausted, true},
+		{"SMTP 552 storage allocation exceeded", FromSMTP(552, "mailbox full"), opstatus.CodeResourceExhausted, false},
+		{"SMTP 450 mailbox unavailable", FromSMTP(450, "mailbox busy"), opstatus.CodeUnavailable, true},
+		{"SMTP 550 mailbox not found", FromSMTP(550, "no such user"), opstatus.CodeNotFound, false},
+		{"FTP 452 insufficient storage", FromFTP(452, "insufficient storage"), opstatus.CodeResourceExhausted, true},
+		{"FTP 552 storage allocation exceeded", FromFTP(552, "quota exceeded"), opstatus.CodeResourceExhausted, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.status.Code() != tt.wantCode {
+				t.Errorf("Code() = %v, want %v", tt.status.Code(), tt.wantCode)
+			}
+			if got := tt.status.IsTransient(); got != tt.wantTransient {
+				t.Errorf("IsTransient() = %v, want %v", got, tt.wantTransient)
+			}
+		})
+	}
+}
+
+func TestRoundTripKeepsTheReplyCode(t *testing.T) {
+	for _, replyCode := range []int{452, 552, 550, 554, 421} {
+		if got := ToSMTP(FromSMTP(replyCode, "failure")); got != replyCode {
+			t.Errorf("ToSMTP(FromSMTP(%d)) = %d", replyCode, got)
+		}
+		if got := ToFTP(FromFTP(replyCode, "failure")); got != replyCode {
+			t.Errorf("ToFTP(FromFTP(%d)) = %d", replyCode, got)
+		}
+	}
+}
+
+func TestToReplyKeepsTheClassOfTheTransience(t *testing.T) {
+	tests := []struct {
+		name   string
+		status *opstatus.Status
+		want   int
+	}{
+		{"permanent ResourceExhausted", opstatus.StatusResourceExhausted.WithTransience(opstatus.Permanent), 554},
+		{"transient ResourceExhausted", opstatus.StatusResourceExhausted.WithDescription("busy"), 452},
+		{"transient FailedPrecondition", opstatus.StatusFailedPrecondition.WithTransience(opstatus.Transient), 451},
+		{"permanent Unavailable", opstatus.StatusUnavailable.WithTransience(opstatus.Permanent), 554},
+		{"OK", opstatus.NewWithCode(opstatus.CodeOK), 250},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToSMTP(tt.status); got != tt.want {
+				t.Errorf("ToSMTP() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package opstatus
+
+import "fmt"
+
+const (
+	// DetailKeyResourceInfo is the detail key of the ResourceInfo attached by NewResourceNotFound.
+	DetailKeyResourceInfo = ReservedDetailKeyPrefix + "resource_info"
+
+	// DetailKeySuggestions is the detail key of the "did you mean" suggestions attached by
+	// NewResourceNotFound.
+	DetailKeySuggestions = ReservedDetailKeyPrefix + "suggestions"
+)
+
+// ResourceInfo describes the resource an operation is about.
+type ResourceInfo struct {
+	// Type is the type of the resource, e.g., "order".
+	Type string `json:"type"`
+	// Name is the name or identifier of the resource.
+	Name string `json:"name"`
+	// Parent is the name of the resource owning it, if any, e.g., "customers/42".
+	Parent string `json:"parent,omitempty"`
+}
+
+func (r ResourceInfo) String() string {
+	if r.Parent == "" {
+		return fmt.Sprintf("%s %q", r.Type, r.Name)
+	}
+	return fmt.Sprintf("%s %q of %s", r.Type, r.Name, r.Parent)
+}
+
+// NewResourceNotFound returns a NotFound status carrying given ResourceInfo and, if any, the names
+// of similar resources the client may have meant.
+func NewResourceNotFound(info ResourceInfo, suggestions ...string) *Status {
+	status := StatusNotFound.WithDescriptionf("%s not found", info)
+	status.setDetail(DetailKeyResourceInfo, info)
+	if len(suggestions) > 0 {
+		status.setDetail(DetailKeySuggestions, suggestions)
+	}
+	return status
+}
+
+// ResourceInfo returns the ResourceInfo attached to this status, if any.
+func (s *Status) ResourceInfo() (ResourceInfo, bool) {
+	info, found := s.details[DetailKeyResourceInfo].(ResourceInfo)
+	return info, found
+}
+
+// Suggestions returns the "did you mean" suggestions attached to this status, if any.
+func (s *Status) Suggestions() []string {
+	suggestions, _ := s.details[DetailKeySuggestions].([]string)
+	return suggestions
+}
@@ -0,0 +1,130 @@
+package opstatus
+
+import (
+	"context"
+	"time"
+)
+
+// DetailKeyAttempts is the detail key of the AttemptsInfo attached by Retry to the statuses of
+// calls that failed after being retried.
+const DetailKeyAttempts = ReservedDetailKeyPrefix + "attempts"
+
+// AttemptsInfo tells how a call was attempted before failing, so that observers can distinguish a
+// call that failed once from one that failed after several retries.
+type AttemptsInfo struct {
+	Count   int           `json:"count"`
+	Elapsed time.Duration `json:"elapsed"`
+	// Codes are the names of the codes of the failed attempts, in order.
+	Codes []string `json:"codes"`
+}
+
+// RetryPolicy configures Retry.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one. It defaults to 3.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. It defaults to 1s, the minimum delay
+	// JustRetryFailingCall advises.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts. It defaults to 30s.
+	MaxBackoff time.Duration
+	// Multiplier is the growth factor of the delay between attempts. It defaults to 2.
+	Multiplier float64
+	// Throttle, if set, may downgrade retries when the recent failure rate is high.
+	Throttle *RetryThrottle
+}
+
+// RetryPolicyByName returns the retry policy with given name declared by the registry documents,
+// e.g., for the calls to a dependency to share one policy configured without redeploying.
+func RetryPolicyByName(name string) (RetryPolicy, bool) {
+	caseRegistryMu.RLock()
+	defer caseRegistryMu.RUnlock()
+	policy, found := registryRetryPolicies[name]
+	return policy, found
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = time.Second
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 30 * time.Second
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	return p
+}
+
+// Retry calls given function until it succeeds, returns a status that isn't retryable, the attempts
+// are exhausted or given context is done. A status is retryable if its transience is set to
+// Transient with WithTransience, or if it isn't set to Permanent and the status advises to retry
+// the failing call. Between attempts it waits for
+// the delay of the RetryInfo of the failure, if any, or for an exponential backoff. A nil status
+// counts as a success.
+//
+// The final failure is annotated with an AttemptsInfo if the call was attempted more than once.
+func Retry(ctx context.Context, policy RetryPolicy, call func(ctx context.Context) *Status) *Status {
+	policy = policy.withDefaults()
+	start := now()
+	backoff := policy.InitialBackoff
+	var codes []string
+	for attempt := 1; ; attempt++ {
+		status := call(ctx)
+		if status == nil || status.IsOK() {
+			if policy.Throttle != nil {
+				policy.Throttle.OnSuccess()
+			}
+			return status
+		}
+		codes = append(codes, status.code.name)
+
+		if !retryable(status) || attempt == policy.MaxAttempts {
+			return annotateAttempts(status, codes, start)
+		}
+		// Only pay the retry once it's certain to happen.
+		if policy.Throttle != nil && !policy.Throttle.pay() {
+			return annotateAttempts(status, codes, start)
+		}
+
+		delay := backoff
+		if info, found := status.RetryInfo(); found && info.RetryDelay > 0 {
+			delay = info.RetryDelay
+		}
+		select {
+		case <-ctx.Done():
+			return annotateAttempts(status, codes, start)
+		case <-after(delay):
+		}
+		backoff = time.Duration(float64(backoff) * policy.Multiplier)
+		if backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+// retryable tells if the failing call of given status may be retried as is.
+func retryable(status *Status) bool {
+	switch status.transience {
+	case Transient:
+		return true
+	case Permanent:
+		return false
+	}
+	return status.RetryAdvice() == JustRetryFailingCall
+}
+
+func annotateAttempts(status *Status, codes []string, start time.Time) *Status {
+	if len(codes) == 1 {
+		return status
+	}
+	annotated := status.derive()
+	annotated.setDetail(DetailKeyAttempts, AttemptsInfo{
+		Count:   len(codes),
+		Elapsed: since(start),
+		Codes:   codes,
+	})
+	return annotated
+}
@@ -21,6 +21,10 @@ var (
 	// retry unless the files are deleted from the directory.
 	NotRetryUntilStateFixed = RetryAdvice("not_retry_until_state_fixed")
 
+	// DoNotRetry means that the client should not retry, even though the status is retryable, e.g.,
+	// because a RetryThrottle found the recent failure rate too high.
+	DoNotRetry = RetryAdvice("do_not_retry")
+
 	// NoAdvice means that for all other status, retry may not be applicable - first ensure your request is idempotent.
 	NoAdvice = RetryAdvice("no_advice")
 )
@@ -0,0 +1,25 @@
+package opstatus
+
+import "time"
+
+// DetailKeyRetryInfo is the detail key of the RetryInfo attached by WithRetryDelay.
+const DetailKeyRetryInfo = ReservedDetailKeyPrefix + "retry_info"
+
+// RetryInfo tells clients how long to wait before retrying.
+type RetryInfo struct {
+	RetryDelay time.Duration `json:"retry_delay"`
+}
+
+// WithRetryDelay returns a derived instance of this Status telling clients to wait for given delay
+// before retrying.
+func (s *Status) WithRetryDelay(delay time.Duration) *Status {
+	derived := s.derive()
+	derived.setDetail(DetailKeyRetryInfo, RetryInfo{RetryDelay: delay})
+	return derived
+}
+
+// RetryInfo returns the RetryInfo attached to this status, if any.
+func (s *Status) RetryInfo() (RetryInfo, bool) {
+	info, found := s.details[DetailKeyRetryInfo].(RetryInfo)
+	return info, found
+}
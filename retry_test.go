@@ -0,0 +1,50 @@
+package opstatus
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRetryDoesNotPayTheFinalAttempt(t *testing.T) {
+	throttle := NewRetryThrottle(100, 10, 1)
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Nanosecond, Throttle: throttle}
+	calls := 0
+	Retry(context.Background(), policy, func(ctx context.Context) *Status {
+		calls++
+		return StatusUnavailable.WithDescription("unavailable")
+	})
+
+	if calls != 3 {
+		t.Fatalf("calls = %d, want 3", calls)
+	}
+	// Two retries followed the three attempts.
+	if got, want := throttle.Budget(), 80.0; got != want {
+		t.Errorf("Budget() = %v, want %v", got, want)
+	}
+}
+
+func TestRetryHonorsTheTransienceOverride(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    *Status
+		wantCalls int
+	}{
+		{"permanent Unavailable", StatusUnavailable.WithTransience(Permanent), 1},
+		{"transient FailedPrecondition", StatusFailedPrecondition.WithTransience(Transient), 3},
+		{"Unavailable", StatusUnavailable.WithDescription("unavailable"), 3},
+		{"FailedPrecondition", StatusFailedPrecondition.WithDescription("closed"), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			Retry(context.Background(), RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Nanosecond}, func(ctx context.Context) *Status {
+				calls++
+				return tt.status
+			})
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
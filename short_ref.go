@@ -0,0 +1,108 @@
+package opstatus
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+const shortRefPrefix = "OPS"
+
+// codeToAbbreviation gives each code a two-letter abbreviation used in short references.
+var codeToAbbreviation = map[Code]string{
+	CodeOK:                 "OK",
+	CodeCancelled:          "CA",
+	CodeUnknown:            "UK",
+	CodeInvalidArgument:    "IA",
+	CodeDeadlineExceeded:   "DE",
+	CodeNotFound:           "NF",
+	CodeAlreadyExists:      "AE",
+	CodePermissionDenied:   "PD",
+	CodeUnauthenticated:    "UA",
+	CodeResourceExhausted:  "RE",
+	CodeFailedPrecondition: "FP",
+	CodeAborted:            "AB",
+	CodeOutOfRange:         "OR",
+	CodeUnimplemented:      "UI",
+	CodeInternal:           "IN",
+	CodeUnavailable:        "UV",
+	CodeDataLoss:           "DL",
+}
+
+// ShortRef is a short, human-quotable reference to an error condition, e.g., OPS-NF-7F3A, that a
+// customer can read to support staff over the phone.
+type ShortRef struct {
+	Code Code
+	// Fingerprint identifies the error condition within the code. It is derived from the case.
+	Fingerprint string
+}
+
+// ShortRef returns the short reference of this status. Statuses with the same code and case have
+// the same reference.
+func (s *Status) ShortRef() ShortRef {
+	caseID := ""
+	if s.theCase != nil {
+		caseID = s.theCase.Identifier()
+	}
+	return ShortRef{
+		Code:        s.code,
+		Fingerprint: shortFingerprint(s.code, caseID),
+	}
+}
+
+// Matches tells if given status has this reference.
+func (r ShortRef) Matches(s *Status) bool {
+	return s.ShortRef() == r
+}
+
+// Resolve returns the registered case this reference was derived from, so that support staff can
+// go from a quoted reference back to the error condition. The reference of a status without case,
+// or of an unregistered case, resolves to nothing. Since fingerprints are short, distinct cases of
+// a code may share one: the first of their identifiers in lexical order is returned.
+func (r ShortRef) Resolve() (RegisteredCase, bool) {
+	caseRegistryMu.RLock()
+	defer caseRegistryMu.RUnlock()
+	var (
+		resolved RegisteredCase
+		found    bool
+	)
+	for id, registered := range caseRegistry {
+		if registered.code != r.Code || shortFingerprint(registered.code, id) != r.Fingerprint {
+			continue
+		}
+		if !found || id < resolved.identifier {
+			resolved, found = registered, true
+		}
+	}
+	return resolved, found
+}
+
+func (r ShortRef) String() string {
+	return shortRefPrefix + "-" + codeToAbbreviation[r.Code] + "-" + r.Fingerprint
+}
+
+// ParseShortRef parses a reference produced by ShortRef.String. It is lenient with letter case and
+// surrounding whitespace, since references are typically typed in by hand.
+func ParseShortRef(ref string) (ShortRef, error) {
+	parts := strings.Split(strings.ToUpper(strings.TrimSpace(ref)), "-")
+	if len(parts) != 3 || parts[0] != shortRefPrefix || len(parts[2]) != 4 {
+		return ShortRef{}, fmt.Errorf("malformed short reference %q", ref)
+	}
+	if _, err := strconv.ParseUint(parts[2], 16, 16); err != nil {
+		return ShortRef{}, fmt.Errorf("malformed fingerprint in short reference %q", ref)
+	}
+	for code, abbreviation := range codeToAbbreviation {
+		if abbreviation == parts[1] {
+			return ShortRef{Code: code, Fingerprint: parts[2]}, nil
+		}
+	}
+	return ShortRef{}, fmt.Errorf("unknown code abbreviation in short reference %q", ref)
+}
+
+func shortFingerprint(code Code, caseID string) string {
+	h := fnv.New32a()
+	_, _ = fmt.Fprintf(h, "%d/%s", code.value, caseID)
+	sum := h.Sum32()
+	return fmt.Sprintf("%04X", (sum>>16)^(sum&0xFFFF))
+}
@@ -0,0 +1,46 @@
+package opstatus
+
+import "testing"
+
+func TestShortRefResolvesToTheRegisteredCase(t *testing.T) {
+	registered, err := RegisterCase("short_ref_test_case", CodeNotFound, "order not found")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := ParseShortRef(registered.Status().ShortRef().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved, found := ref.Resolve()
+	if !found || resolved.Identifier() != registered.Identifier() || resolved.Code() != CodeNotFound {
+		t.Errorf("Resolve() = %v, %v, want %v", resolved.Identifier(), found, registered.Identifier())
+	}
+
+	if _, found := NewWithCode(CodeNotFound).ShortRef().Resolve(); found {
+		t.Error("the reference of a status without case resolved")
+	}
+	other := ShortRef{Code: CodeInternal, Fingerprint: ref.Fingerprint}
+	if _, found := other.Resolve(); found {
+		t.Error("a reference of another code resolved")
+	}
+}
+
+func TestParseShortRef(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    ShortRef
+		wantErr bool
+	}{
+		{" ops-nf-7f3a ", ShortRef{Code: CodeNotFound, Fingerprint: "7F3A"}, false},
+		{"OPS-NF-ZZZZ", ShortRef{}, true},
+		{"OPS-NF-+7F3", ShortRef{}, true},
+		{"OPS-XX-7F3A", ShortRef{}, true},
+		{"OPS-NF-7F3", ShortRef{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseShortRef(tt.ref)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseShortRef(%q) = %v, %v, want %v, error %v", tt.ref, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+// Package soap converts statuses to SOAP 1.1 and 1.2 faults and back, for integrations with legacy
+// partners that still speak SOAP.
+package soap
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/ikonglong/op-status"
+)
+
+const (
+	envelope11NS = "http://schemas.xmlsoap.org/soap/envelope/"
+	envelope12NS = "http://www.w3.org/2003/05/soap-envelope"
+)
+
+// Version is a SOAP version.
+type Version int
+
+const (
+	SOAP11 Version = iota
+	SOAP12
+)
+
+// detail is the detail element carrying the status, shared by both SOAP versions.
+type detail struct {
+	XMLName xml.Name      `xml:"urn:ikonglong:op-status status"`
+	Code    string        `xml:"code"`
+	Case    string        `xml:"case,omitempty"`
+	Entries []detailEntry `xml:"detail"`
+}
+
+// detailEntry is a detail of the status. The reserved details are encoded as JSON, so that they are
+// restored with their types; the others are encoded as strings.
+type detailEntry struct {
+	Key   string `xml:"key,attr"`
+	Type  string `xml:"type,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+const jsonEntry = "json"
+
+// DetailKeyUnknownCode is the detail key under which Parse records the name of a code or subcode
+// it doesn't know, e.g., one added by a newer peer.
+const DetailKeyUnknownCode = "soap_unknown_code"
+
+type envelope11 struct {
+	XMLName xml.Name `xml:"soap:Envelope"`
+	NS      string   `xml:"xmlns:soap,attr"`
+	Fault   struct {
+		Code   string `xml:"faultcode"`
+		String string `xml:"faultstring"`
+		Detail detail `xml:"detail>status"`
+	} `xml:"soap:Body>soap:Fault"`
+}
+
+type envelope12 struct {
+	XMLName xml.Name `xml:"env:Envelope"`
+	NS      string   `xml:"xmlns:env,attr"`
+	Fault   struct {
+		Code    string `xml:"env:Code>env:Value"`
+		Subcode string `xml:"env:Code>env:Subcode>env:Value"`
+		Reason  struct {
+			Lang string `xml:"xml:lang,attr"`
+			Text string `xml:",chardata"`
+		} `xml:"env:Reason>env:Text"`
+		Detail detail `xml:"env:Detail>status"`
+	} `xml:"env:Body>env:Fault"`
+}
+
+// Encode renders given status as a SOAP envelope containing a fault of given version. The fault
+// code tells whether the sender or the receiver is at fault, derived from the HTTP status of the
+// status; the fault string is the description; the detail element carries the code, case and
+// details.
+func Encode(status *opstatus.Status, version Version) ([]byte, error) {
+	d, err := toDetail(status)
+	if err != nil {
+		return nil, err
+	}
+	reason := status.Description()
+	if reason == "" {
+		reason = status.Code().Name()
+	}
+	senderFault := status.HTTPStatus() < 500
+
+	var env any
+	switch version {
+	case SOAP11:
+		e := &envelope11{NS: envelope11NS}
+		e.Fault.Code = "soap:Server"
+		if senderFault {
+			e.Fault.Code = "soap:Client"
+		}
+		e.Fault.String = reason
+		e.Fault.Detail = d
+		env = e
+	case SOAP12:
+		e := &envelope12{NS: envelope12NS}
+		e.Fault.Code = "env:Receiver"
+		if senderFault {
+			e.Fault.Code = "env:Sender"
+		}
+		e.Fault.Subcode = status.Code().Name()
+		e.Fault.Reason.Lang = "en"
+		e.Fault.Reason.Text = reason
+		e.Fault.Detail = d
+		env = e
+	default:
+		return nil, fmt.Errorf("unknown SOAP version %d", version)
+	}
+	body, err := xml.Marshal(env)
+	if err != nil {
+		return nil, fmt.Errorf("encode SOAP fault: %w", err)
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+func toDetail(status *opstatus.Status) (detail, error) {
+	d := detail{Code: status.Code().Name()}
+	if theCase := status.TheCase(); theCase != nil {
+		d.Case = theCase.Identifier()
+	}
+	for _, key := range status.DetailKeys() {
+		value := status.Details()[key]
+		if !strings.HasPrefix(key, opstatus.ReservedDetailKeyPrefix) {
+			d.Entries = append(d.Entries, detailEntry{Key: key, Value: fmt.Sprint(value)})
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return detail{}, fmt.Errorf("encode detail %s: %w", key, err)
+		}
+		d.Entries = append(d.Entries, detailEntry{Key: key, Type: jsonEntry, Value: string(encoded)})
+	}
+	return d, nil
+}
+
+// parsed matches the faults of both SOAP versions by local names.
+type parsed struct {
+	Body struct {
+		Fault *struct {
+			FaultCode   string  `xml:"faultcode"`
+			FaultString string  `xml:"faultstring"`
+			Code        string  `xml:"Code>Value"`
+			Subcode     string  `xml:"Code>Subcode>Value"`
+			Reason      string  `xml:"Reason>Text"`
+			Detail11    *detail `xml:"detail>status"`
+			Detail12    *detail `xml:"Detail>status"`
+		} `xml:"Fault"`
+	} `xml:"Body"`
+}
+
+// Parse parses a SOAP 1.1 or 1.2 envelope containing a fault into a status. Faults encoded by this
+// package are restored with their code, case and details: the reserved ones with their types, the
+// others as strings. Faults from other systems are classified by their fault code: sender faults
+// become InvalidArgument, version mismatches and unsupported mandatory headers become
+// Unimplemented, and anything else becomes Internal. A code or subcode that isn't known locally is
+// kept as a DetailKeyUnknownCode detail.
+func Parse(data []byte) (*opstatus.Status, error) {
+	var p parsed
+	if err := xml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("decode SOAP envelope: %w", err)
+	}
+	fault := p.Body.Fault
+	if fault == nil {
+		return nil, fmt.Errorf("SOAP envelope contains no fault")
+	}
+	description := fault.FaultString
+	if description == "" {
+		description = fault.Reason
+	}
+	d := fault.Detail11
+	if d == nil {
+		d = fault.Detail12
+	}
+
+	code, name, found := codeOf(d, fault.Subcode)
+	var status *opstatus.Status
+	if found {
+		status = opstatus.NewWithCode(code).WithDescription(description)
+	} else {
+		status = classify(fault.FaultCode + fault.Code).WithDescription(description)
+	}
+	if d != nil {
+		var err error
+		if status, err = restore(status, d); err != nil {
+			return nil, err
+		}
+	}
+	if !found && name != "" {
+		status = status.WithDetails(DetailKeyUnknownCode, name)
+	}
+	return status, nil
+}
+
+// restore returns given status with the case and details of given detail element. It goes through
+// the JSON encoding of statuses, so that the reserved details are decoded into their types.
+func restore(status *opstatus.Status, d *detail) (*opstatus.Status, error) {
+	details := map[string]json.RawMessage{}
+	for _, entry := range d.Entries {
+		if entry.Type == jsonEntry {
+			details[entry.Key] = json.RawMessage(entry.Value)
+			continue
+		}
+		if strings.HasPrefix(entry.Key, opstatus.ReservedDetailKeyPrefix) {
+			continue
+		}
+		value, err := json.Marshal(entry.Value)
+		if err != nil {
+			return nil, err
+		}
+		details[entry.Key] = value
+	}
+	encoded, err := json.Marshal(map[string]any{
+		"code":        status.Code().Value(),
+		"code_name":   status.Code().Name(),
+		"case":        d.Case,
+		"description": status.Description(),
+		"details":     details,
+	})
+	if err != nil {
+		return nil, err
+	}
+	restored := &opstatus.Status{}
+	if err := json.Unmarshal(encoded, restored); err != nil {
+		return nil, fmt.Errorf("decode SOAP fault detail: %w", err)
+	}
+	return restored, nil
+}
+
+// codeOf returns the code named by given detail element, or else by given subcode, with the name.
+func codeOf(d *detail, subcode string) (opstatus.Code, string, bool) {
+	name := localName(subcode)
+	if d != nil && d.Code != "" {
+		name = d.Code
+	}
+	code, found := opstatus.CodeByName(name)
+	return code, name, found
+}
+
+func classify(faultCode string) *opstatus.Status {
+	switch localName(faultCode) {
+	case "Client", "Sender":
+		return opstatus.StatusInvalidArgument.WithDescription("")
+	case "VersionMismatch", "MustUnderstand":
+		return opstatus.StatusUnimplemented.WithDescription("")
+	default:
+		return opstatus.StatusInternal.WithDescription("")
+	}
+}
+
+func localName(qualified string) string {
+	qualified = strings.TrimSpace(qualified)
+	if i := strings.LastIndex(qualified, ":"); i >= 0 {
+		return qualified[i+1:]
+	}
+	return qualified
+}
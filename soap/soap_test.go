@@ -0,0 +1,97 @@
+package soap
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ikonglong/op-status"
+)
+
+func TestEncodeParseRoundTrip(t *testing.T) {
+	badRequest := opstatus.NewBadRequest(opstatus.FieldViolation{Field: "/quantity", Description: "must be positive"}).
+		WithCase(opstatus.CaseID("invalid_order"))
+	badRequest.AddDetail("order_id", "o-1")
+
+	tests := []struct {
+		name   string
+		status *opstatus.Status
+	}{
+		{"field violations", badRequest},
+		{"HTTP override", opstatus.NewPayloadTooLarge(10, 20)},
+		{"foreign code", opstatus.NewWithForeignCode(42, "Future")},
+	}
+	for _, tt := range tests {
+		for _, version := range []Version{SOAP11, SOAP12} {
+			t.Run(tt.name, func(t *testing.T) {
+				data, err := Encode(tt.status, version)
+				if err != nil {
+					t.Fatal(err)
+				}
+				parsed, err := Parse(data)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if parsed.Code() != tt.status.Code() || parsed.Description() != tt.status.Description() ||
+					!opstatus.CaseEqual(parsed.TheCase(), tt.status.TheCase()) {
+					t.Errorf("Parse(Encode()) = %v %q %v, want %v %q %v", parsed.Code(), parsed.Description(), parsed.TheCase(),
+						tt.status.Code(), tt.status.Description(), tt.status.TheCase())
+				}
+				if !reflect.DeepEqual(parsed.Details(), tt.status.Details()) {
+					t.Errorf("details = %#v, want %#v", parsed.Details(), tt.status.Details())
+				}
+				if parsed.HTTPStatus() != tt.status.HTTPStatus() {
+					t.Errorf("HTTPStatus() = %d, want %d", parsed.HTTPStatus(), tt.status.HTTPStatus())
+				}
+			})
+		}
+	}
+}
+
+func TestEncodeUsesTheHTTPOverride(t *testing.T) {
+	// An Unavailable status, mapped to 503, overridden to 429: the sender is at fault.
+	var status opstatus.Status
+	err := json.Unmarshal([]byte(`{"code": 14, "code_name": "ServiceUnavailable", "details": {"opstatus.io/http_status": 429}}`), &status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := Encode(&status, SOAP12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "env:Sender") {
+		t.Errorf("fault is not a sender fault: %s", data)
+	}
+}
+
+func TestParseKeepsUnknownCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		envelope string
+		want     opstatus.Code
+	}{
+		{"detail code", `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><soap:Fault>
+			<faultcode>soap:Client</faultcode><faultstring>slow down</faultstring>
+			<detail><status xmlns="urn:ikonglong:op-status"><code>Throttled</code></status></detail>
+			</soap:Fault></soap:Body></soap:Envelope>`, opstatus.CodeInvalidArgument},
+		{"subcode", `<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope"><env:Body><env:Fault>
+			<env:Code><env:Value>env:Receiver</env:Value><env:Subcode><env:Value>m:Throttled</env:Value></env:Subcode></env:Code>
+			<env:Reason><env:Text>slow down</env:Text></env:Reason>
+			</env:Fault></env:Body></env:Envelope>`, opstatus.CodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := Parse([]byte(tt.envelope))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status.Code() != tt.want {
+				t.Errorf("Code() = %v, want %v", status.Code(), tt.want)
+			}
+			if name, _ := status.Detail(DetailKeyUnknownCode); name != "Throttled" {
+				t.Errorf("unknown code = %v, want Throttled", name)
+			}
+		})
+	}
+}
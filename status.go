@@ -3,13 +3,12 @@ package opstatus
 import (
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 
 	"github.com/ikonglong/op-status/http"
 )
 
-type any interface{}
-
 // A pseudo-enum of Status instances mapped 1:1 with the Codes. This simplifies construction
 // patterns for derived instances of Status.
 var (
@@ -196,18 +195,23 @@ var codeToHTTPStatus = map[Code]http.Status{
 }
 
 var httpStatusToOpStatus = map[http.Status]Status{
-	http.StatusOK:                  StatusOK,
-	http.StatusBadRequest:          StatusInvalidArgument,
-	http.StatusUnauthorized:        StatusUnauthenticated,
-	http.StatusForbidden:           StatusPermissionDenied,
-	http.StatusNotFound:            StatusNotFound,
-	http.StatusConflict:            StatusAlreadyExists,
-	http.StatusTooManyRequests:     StatusResourceExhausted,
-	http.StatusClientClosedRequest: StatusCancelled,
-	http.StatusInternalServerError: StatusInternal,
-	http.StatusNotImplemented:      StatusUnimplemented,
-	http.StatusServiceUnavailable:  StatusUnavailable,
-	http.StatusTimeout:             StatusDeadlineExceeded,
+	http.StatusOK:                   StatusOK,
+	http.StatusBadRequest:           StatusInvalidArgument,
+	http.StatusUnauthorized:         StatusUnauthenticated,
+	http.StatusForbidden:            StatusPermissionDenied,
+	http.StatusNotFound:             StatusNotFound,
+	http.StatusConflict:             StatusAlreadyExists,
+	http.StatusGone:                 StatusNotFound,
+	http.StatusPreconditionFailed:   StatusFailedPrecondition,
+	http.StatusPayloadTooLarge:      StatusResourceExhausted,
+	http.StatusUnsupportedMediaType: StatusInvalidArgument,
+	http.StatusUnprocessableEntity:  StatusFailedPrecondition,
+	http.StatusTooManyRequests:      StatusResourceExhausted,
+	http.StatusClientClosedRequest:  StatusCancelled,
+	http.StatusInternalServerError:  StatusInternal,
+	http.StatusNotImplemented:       StatusUnimplemented,
+	http.StatusServiceUnavailable:   StatusUnavailable,
+	http.StatusTimeout:              StatusDeadlineExceeded,
 }
 
 // NewByHTTPStatus returns a copy of the status prototype mapped to given http status code.
@@ -220,29 +224,35 @@ func NewByHTTPStatus(statusCode int) *Status {
 	// Internally assure that there must be a unique op-status mapped to any defined https status
 	// in order that the caller can take the fluid coding style.
 	opStatus, found := httpStatusToOpStatus[http.Status(statusCode)]
-	if found {
+	if !found {
 		log.Printf("[OpError] not found op-status mapped to given defined http status %v\n", statusCode)
+		opStatus = StatusUnknown
 	}
 	return &opStatus
 }
 
-// NewWithCodeValue returns a copy of the status prototype mapped to given op status code.
+// NewWithCodeValue returns a copy of the status prototype mapped to given op status code, described
+// by the default message of the code, if any.
 func NewWithCodeValue(codeValue int) *Status {
 	if codeValue < 0 || codeValue >= len(statusList) {
-		return StatusUnknown.WithDescriptionf("Unknown op status code: %v", codeValue)
+		return NewWithForeignCode(codeValue, "")
 	}
-	return &statusList[codeValue]
+	return NewWithCode(statusList[codeValue].code)
 }
 
-// NewWithCode returns a copy of the status prototype mapped to given op status code.
+// NewWithCode returns a copy of the status prototype mapped to given op status code, described by
+// the default message of the code, if any.
 func NewWithCode(code Code) *Status {
-	return &statusList[code.value]
+	status := statusList[code.value].derive()
+	status.description = DefaultMessage(code)
+	return status
 }
 
 // Status defines the status of an operation by providing a standard Code in conjunction with an
 // optional Case and an optional description. Instances of Status are created by starting with the
 // template for the appropriate Code and supplementing it with additional information:
-//  StatusNotFound.WithDescription("Could not find 'important_file.txt'")
+//
+//	StatusNotFound.WithDescription("Could not find 'important_file.txt'")
 //
 // The logical error model that Status defines is suitable for different programming environments,
 // including REST APIs and RPC APIs.
@@ -251,6 +261,8 @@ type Status struct {
 	theCase     Case
 	description string
 	details     map[string]any
+	cause       error
+	transience  Transience
 }
 
 func newStatus(code Code) Status {
@@ -264,29 +276,29 @@ func newStatus(code Code) Status {
 func (s *Status) WithDescription(description string) *Status {
 	description = strings.TrimSpace(description)
 	if s.description == description {
-		copy := *s
-		return &copy // return a copy of this Status
+		return s.derive() // return a copy of this Status
 	}
 	return &Status{
 		code:        s.code,
 		theCase:     s.theCase,
 		description: description,
 		details:     copyDetails(s.details),
+		cause:       s.cause,
+		transience:  s.transience,
 	}
 }
 
 // WithDescriptionf returns a derived instance of this Status with the formatted description. Leading and
 // trailing whitespace is removed.
 func (s *Status) WithDescriptionf(descFmt string, fmtArgs ...any) *Status {
-	return s.WithDescription(fmt.Sprintf(descFmt, fmtArgs))
+	return s.WithDescription(fmt.Sprintf(descFmt, fmtArgs...))
 }
 
 // AugmentDescription returns a derived instance of this Status augmenting the current description
 // with additional detail.
 func (s *Status) AugmentDescription(additionalDetail string) *Status {
 	if additionalDetail == "" {
-		copy := *s
-		return &copy // return a copy of this Status
+		return s.derive() // return a copy of this Status
 	}
 
 	newMsg := ""
@@ -300,45 +312,62 @@ func (s *Status) AugmentDescription(additionalDetail string) *Status {
 
 // WithCase returns a derived instance of this Status with the given case.
 func (s *Status) WithCase(theCase Case) *Status {
-	if s.theCase == theCase { // todo 深度比较 case
-		copy := *s
-		return &copy // return a copy of this Status
+	if CaseEqual(s.theCase, theCase) {
+		return s.derive() // return a copy of this Status
 	}
 	return &Status{
 		code:        s.code,
 		theCase:     theCase,
 		description: s.description,
-		details:     s.details,
+		details:     copyDetails(s.details),
+		cause:       s.cause,
+		transience:  s.transience,
 	}
 }
 
 // WithCaseAndDesc returns a derived instance of this Status with the given case and description.
 func (s *Status) WithCaseAndDesc(theCase Case, description string) *Status {
 	description = strings.TrimSpace(description)
-	if s.theCase == theCase && s.description == description { // todo 深度比较 case
-		copy := *s
-		return &copy
+	if CaseEqual(s.theCase, theCase) && s.description == description {
+		return s.derive()
 	}
 	return &Status{
 		code:        s.code,
 		theCase:     theCase,
 		description: description,
 		details:     copyDetails(s.details),
+		cause:       s.cause,
+		transience:  s.transience,
 	}
 }
 
 // WithCaseAndDescf returns a derived instance of this Status with the given case and formatted description.
 func (s *Status) WithCaseAndDescf(theCase Case, descFmt string, fmtArgs ...any) *Status {
-	desc := fmt.Sprintf(descFmt, fmtArgs)
+	desc := fmt.Sprintf(descFmt, fmtArgs...)
 	return s.WithCaseAndDesc(theCase, desc)
 }
 
-// AddDetail adds a detail about the failure.
+// WithCause returns a derived instance of this Status carrying the given underlying error. The cause
+// is meant for logging and error inspection; it is not part of the status itself.
+func (s *Status) WithCause(cause error) *Status {
+	derived := s.derive()
+	derived.cause = cause
+	return derived
+}
+
+// AddDetail adds a detail about the failure. The key is normalized as configured by
+// SetDetailKeyNormalization. Keys under ReservedDetailKeyPrefix are reserved for the built-in typed
+// details and are ignored.
 func (s *Status) AddDetail(key string, value any) {
-	key = strings.TrimSpace(key)
-	if key == "" {
+	key = NormalizeDetailKey(key)
+	if key == "" || isReservedDetailKey(key) {
 		return
 	}
+	s.setDetail(key, value)
+}
+
+// setDetail sets a detail without checking its key, so that built-in typed details can be set.
+func (s *Status) setDetail(key string, value any) {
 	s.details[key] = value
 }
 
@@ -361,10 +390,35 @@ func (s *Status) TheCase() Case {
 	return s.theCase
 }
 
+// Cause returns the underlying error carried by this status, if any.
+func (s *Status) Cause() error {
+	return s.cause
+}
+
+// Err returns an OpError with this status, wrapping its cause. If this status is OK, nil is returned.
+func (s *Status) Err() error {
+	if s.IsOK() {
+		return nil
+	}
+	return NewOpError(*s)
+}
+
 func (s *Status) Details() map[string]any {
 	return s.details
 }
 
+// DetailKeys returns the keys of the details of this status in sorted order. Encoders should emit
+// details in this order so that serialized statuses are reproducible, e.g., for golden tests,
+// signing and deduplication.
+func (s *Status) DetailKeys() []string {
+	keys := make([]string, 0, len(s.details))
+	for key := range s.details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // IsOK tells if this status is OK, i.e., not an error
 func (s *Status) IsOK() bool {
 	return s.code == CodeOK
@@ -391,6 +445,19 @@ func (s *Status) RetryAdvice() RetryAdvice {
 	return advice
 }
 
+// Clone returns a copy of this Status that does not share its details, e.g., to keep a status
+// beyond the control of its producer.
+func (s *Status) Clone() *Status {
+	return s.derive()
+}
+
+// derive returns a copy of this Status that does not share its details.
+func (s *Status) derive() *Status {
+	derived := *s
+	derived.details = copyDetails(s.details)
+	return &derived
+}
+
 func copyDetails(details map[string]any) map[string]any {
 	if details == nil {
 		return map[string]any{}
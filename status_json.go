@@ -0,0 +1,140 @@
+package opstatus
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ikonglong/op-status/http"
+)
+
+// statusJSON is the wire format of a Status.
+type statusJSON struct {
+	Code        int                        `json:"code"`
+	CodeName    string                     `json:"code_name,omitempty"`
+	Case        json.RawMessage            `json:"case,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Transience  string                     `json:"transience,omitempty"`
+	Details     map[string]json.RawMessage `json:"details,omitempty"`
+}
+
+var transienceToName = map[Transience]string{
+	Transient: "transient",
+	Permanent: "permanent",
+}
+
+// reservedDetailDecoders decode the reserved details into their types, so that the typed accessors
+// work on decoded statuses.
+var reservedDetailDecoders = map[string]func(data json.RawMessage) (any, error){
+	DetailKeyResourceInfo:        decodeDetail[ResourceInfo],
+	DetailKeySuggestions:         decodeDetail[[]string],
+	DetailKeyContractViolation:   decodeDetail[ContractViolation],
+	DetailKeyRangeInfo:           decodeDetail[RangeInfo],
+	DetailKeyExistingResource:    decodeDetail[ExistingResource],
+	DetailKeyDataIntegrityInfo:   decodeDetail[DataIntegrityInfo],
+	DetailKeyOperationID:         decodeDetail[string],
+	DetailKeyAttempts:            decodeDetail[AttemptsInfo],
+	DetailKeyFieldViolations:     decodeDetail[[]FieldViolation],
+	DetailKeyFailures:            decodeDetail[[]string],
+	DetailKeyDependency:          decodeDetail[DependencyInfo],
+	DetailKeyConflictInfo:        decodeDetail[ConflictInfo],
+	DetailKeyDeadlineInfo:        decodeDetail[DeadlineInfo],
+	DetailKeyHTTPStatus:          decodeDetail[http.Status],
+	DetailKeyPayloadLimit:        decodeDetail[PayloadLimit],
+	DetailKeySupportedMediaTypes: decodeDetail[[]string],
+	DetailKeyDenialInfo:          decodeDetail[DenialInfo],
+	DetailKeyRetryInfo:           decodeDetail[RetryInfo],
+	DetailKeyForeignCode:         decodeDetail[ForeignCode],
+	DetailKeyLocation:            decodeDetail[string],
+}
+
+func decodeDetail[T any](data json.RawMessage) (any, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// MarshalJSON encodes the code, case, description, transience and details of this status. The
+// cause is left out since it is not meant for the peers. The case is encoded by EncodeCase and a
+// foreign code is encoded as the code itself. It has a value receiver so that statuses held by
+// value, e.g., in structs, are encoded too.
+func (s Status) MarshalJSON() ([]byte, error) {
+	encoded := statusJSON{
+		Code:        s.code.value,
+		CodeName:    s.code.name,
+		Description: s.description,
+		Transience:  transienceToName[s.transience],
+	}
+	if foreign, found := s.ForeignCode(); found {
+		encoded.Code = foreign.Value
+		encoded.CodeName = foreign.Name
+	}
+	if s.theCase != nil {
+		theCase, err := EncodeCase(s.theCase)
+		if err != nil {
+			return nil, err
+		}
+		encoded.Case = theCase
+	}
+	for _, key := range s.DetailKeys() {
+		if key == DetailKeyForeignCode {
+			continue
+		}
+		value, err := json.Marshal(s.details[key])
+		if err != nil {
+			return nil, fmt.Errorf("encode detail %s: %w", key, err)
+		}
+		if encoded.Details == nil {
+			encoded.Details = make(map[string]json.RawMessage, len(s.details))
+		}
+		encoded.Details[key] = value
+	}
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON decodes a status encoded by MarshalJSON. A code unknown locally decodes to an
+// Unknown status recording the ForeignCode. The reserved details decode into their types, e.g.,
+// the FieldViolations, the others into the types encoding/json decodes into an interface value and
+// are added like AddDetail does: their keys are normalized, and the ones that look reserved but
+// aren't known are dropped.
+func (s *Status) UnmarshalJSON(data []byte) error {
+	var encoded statusJSON
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	decoded := NewWithForeignCode(encoded.Code, encoded.CodeName)
+	if code, found := CodeByName(encoded.CodeName); found && code.value == encoded.Code || encoded.CodeName == "" {
+		decoded = NewWithCodeValue(encoded.Code).derive()
+	}
+	if encoded.Case != nil {
+		theCase, err := DecodeCase(encoded.Case)
+		if err != nil {
+			return err
+		}
+		decoded.theCase = theCase
+	}
+	if encoded.Description != "" {
+		decoded.description = encoded.Description
+	}
+	for transience, name := range transienceToName {
+		if encoded.Transience == name {
+			decoded.transience = transience
+		}
+	}
+	for key, data := range encoded.Details {
+		if decode, found := reservedDetailDecoders[key]; found {
+			value, err := decode(data)
+			if err != nil {
+				return fmt.Errorf("decode detail %s: %w", key, err)
+			}
+			decoded.setDetail(key, value)
+			continue
+		}
+		var value any
+		if err := json.Unmarshal(data, &value); err != nil {
+			return fmt.Errorf("decode detail %s: %w", key, err)
+		}
+		decoded.AddDetail(key, value)
+	}
+	*s = *decoded
+	return nil
+}
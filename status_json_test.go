@@ -0,0 +1,33 @@
+package opstatus
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUnmarshalJSONNormalizesDetailKeys(t *testing.T) {
+	if err := SetDetailKeyNormalization(SnakeCaseKeys); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetDetailKeyNormalization(0) })
+
+	data := []byte(`{"code": 3, "code_name": "InvalidArgument", "details": {
+		"orderID": "o-1",
+		"opstatus.io/unknown": "x",
+		"opstatus.io/field_violations": [{"field": "/a", "description": "bad"}]
+	}}`)
+	var status Status
+	if err := json.Unmarshal(data, &status); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, found := status.Details()["order_id"]; !found || value != "o-1" {
+		t.Errorf("details[order_id] = %v, %v, want o-1", value, found)
+	}
+	if _, found := status.Details()["opstatus.io/unknown"]; found {
+		t.Errorf("unknown reserved detail was decoded")
+	}
+	if violations := status.FieldViolations(); len(violations) != 1 || violations[0].Field != "/a" {
+		t.Errorf("FieldViolations() = %v, want the decoded violation", violations)
+	}
+}
@@ -0,0 +1,15 @@
+package opstatus
+
+import "testing"
+
+func TestNewWithCodeDoesNotShareThePrototype(t *testing.T) {
+	NewWithCode(CodeNotFound).AddDetail("leak", "x")
+	NewWithCodeValue(CodeNotFound.Value()).AddDetail("leak", "y")
+
+	if details := NewWithCode(CodeNotFound).Details(); len(details) != 0 {
+		t.Errorf("NewWithCode(CodeNotFound).Details() = %v, want none", details)
+	}
+	if details := NewWithCodeValue(CodeNotFound.Value()).Details(); len(details) != 0 {
+		t.Errorf("NewWithCodeValue(%d).Details() = %v, want none", CodeNotFound.Value(), details)
+	}
+}
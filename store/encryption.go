@@ -0,0 +1,194 @@
+package store
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ikonglong/op-status"
+)
+
+// KeyManager provides the data keys of envelope encryption, e.g., backed by a cloud KMS: each
+// stored status is encrypted with a fresh data key, itself encrypted by a master key that never
+// leaves the KeyManager.
+type KeyManager interface {
+	// GenerateDataKey returns a new 256-bit data key, in plain and encrypted by the master key.
+	GenerateDataKey(ctx context.Context) (plaintext, encrypted []byte, err error)
+
+	// DecryptDataKey returns the plain data key of given encrypted one. It fails for the callers
+	// not authorized to read the encrypted details.
+	DecryptDataKey(ctx context.Context, encrypted []byte) ([]byte, error)
+}
+
+// encryptedValuePrefix starts the encrypted detail values, which are strings so that they survive
+// the JSON encoding of the statuses unchanged.
+const encryptedValuePrefix = "opstatus-encrypted:v1:"
+
+// EncryptingStore is a StatusStore encrypting the values of sensitive details before storing them
+// in another StatusStore, e.g., a SQLStore, and decrypting them when reading them back. The values
+// are encoded to JSON before being encrypted, so they are decrypted into the types encoding/json
+// decodes into an interface value. It is safe for concurrent use if the stores and key manager are.
+type EncryptingStore struct {
+	store StatusStore
+	keys  KeyManager
+	// detailKeys are the keys of the details to encrypt. If empty, the details marked with
+	// opstatus.RedactDetails are.
+	detailKeys []string
+}
+
+// NewEncryptingStore returns an EncryptingStore encrypting the details with given keys, or the ones
+// marked with opstatus.RedactDetails if none, with data keys of given key manager.
+func NewEncryptingStore(store StatusStore, keys KeyManager, detailKeys ...string) *EncryptingStore {
+	normalized := make([]string, len(detailKeys))
+	for i, key := range detailKeys {
+		normalized[i] = opstatus.NormalizeDetailKey(key)
+	}
+	return &EncryptingStore{store: store, keys: keys, detailKeys: normalized}
+}
+
+func (e *EncryptingStore) Put(ctx context.Context, operationID string, status *opstatus.Status, ttl time.Duration) error {
+	if err := checkPut(operationID, status); err != nil {
+		return err
+	}
+	encrypted, err := e.encrypt(ctx, status)
+	if err != nil {
+		return err
+	}
+	return e.store.Put(ctx, operationID, encrypted, ttl)
+}
+
+func (e *EncryptingStore) Get(ctx context.Context, operationID string) (*Record, error) {
+	record, err := e.store.Get(ctx, operationID)
+	if err != nil {
+		return nil, err
+	}
+	if record.Status, err = e.decrypt(ctx, record.Status); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (e *EncryptingStore) FindByCode(ctx context.Context, code opstatus.Code) ([]*Record, error) {
+	records, err := e.store.FindByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.Status, err = e.decrypt(ctx, record.Status); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// encrypt returns a copy of given status whose sensitive details are encrypted with a fresh data
+// key. The status is returned as is if it has none.
+func (e *EncryptingStore) encrypt(ctx context.Context, status *opstatus.Status) (*opstatus.Status, error) {
+	detailKeys := e.detailKeys
+	if len(detailKeys) == 0 {
+		detailKeys = opstatus.RedactedDetailKeys()
+	}
+	var sensitive []string
+	for _, key := range detailKeys {
+		if _, found := status.Detail(key); found {
+			sensitive = append(sensitive, key)
+		}
+	}
+	if len(sensitive) == 0 {
+		return status, nil
+	}
+
+	plainKey, encryptedKey, err := e.keys.GenerateDataKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("generate data key: %w", err)
+	}
+	aead, err := newAEAD(plainKey)
+	if err != nil {
+		return nil, err
+	}
+	encrypted := status.Clone()
+	for _, key := range sensitive {
+		value, _ := status.Detail(key)
+		plaintext, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("encode detail %s: %w", key, err)
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, err
+		}
+		// The detail key is authenticated, so that encrypted values can't be swapped between details.
+		ciphertext := aead.Seal(nil, nonce, plaintext, []byte(key))
+		encrypted = encrypted.WithDetails(key, encryptedValuePrefix+strings.Join([]string{
+			base64.RawStdEncoding.EncodeToString(encryptedKey),
+			base64.RawStdEncoding.EncodeToString(nonce),
+			base64.RawStdEncoding.EncodeToString(ciphertext),
+		}, ":"))
+	}
+	return encrypted, nil
+}
+
+// decrypt returns given status with its encrypted details decrypted.
+func (e *EncryptingStore) decrypt(ctx context.Context, status *opstatus.Status) (*opstatus.Status, error) {
+	decrypted := status
+	plainKeys := map[string][]byte{}
+	for _, key := range status.DetailKeys() {
+		value, _ := status.Detail(key)
+		encoded, isString := value.(string)
+		if !isString || !strings.HasPrefix(encoded, encryptedValuePrefix) {
+			continue
+		}
+		parts := strings.Split(strings.TrimPrefix(encoded, encryptedValuePrefix), ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("decrypt detail %s: malformed value", key)
+		}
+		var decoded [3][]byte
+		for i, part := range parts {
+			var err error
+			if decoded[i], err = base64.RawStdEncoding.DecodeString(part); err != nil {
+				return nil, fmt.Errorf("decrypt detail %s: %w", key, err)
+			}
+		}
+		encryptedKey, nonce, ciphertext := decoded[0], decoded[1], decoded[2]
+
+		plainKey, found := plainKeys[parts[0]]
+		if !found {
+			var err error
+			if plainKey, err = e.keys.DecryptDataKey(ctx, encryptedKey); err != nil {
+				return nil, fmt.Errorf("decrypt data key: %w", err)
+			}
+			plainKeys[parts[0]] = plainKey
+		}
+		aead, err := newAEAD(plainKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(nonce) != aead.NonceSize() {
+			return nil, fmt.Errorf("decrypt detail %s: malformed nonce", key)
+		}
+		plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(key))
+		if err != nil {
+			return nil, fmt.Errorf("decrypt detail %s: %w", key, err)
+		}
+		var original any
+		if err := json.Unmarshal(plaintext, &original); err != nil {
+			return nil, fmt.Errorf("decode detail %s: %w", key, err)
+		}
+		decrypted = decrypted.WithDetails(key, original)
+	}
+	return decrypted, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("data key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ikonglong/op-status"
+)
+
+// fakeKeyManager "encrypts" data keys by reversing them, and refuses to decrypt them unless
+// authorized.
+type fakeKeyManager struct {
+	authorized bool
+}
+
+func (m fakeKeyManager) GenerateDataKey(context.Context) ([]byte, []byte, error) {
+	key := bytes.Repeat([]byte{7, 1}, 16)
+	return key, reversed(key), nil
+}
+
+func (m fakeKeyManager) DecryptDataKey(_ context.Context, encrypted []byte) ([]byte, error) {
+	if !m.authorized {
+		return nil, errors.New("not authorized")
+	}
+	return reversed(encrypted), nil
+}
+
+func reversed(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return r
+}
+
+func TestEncryptingStoreEncryptsSensitiveDetailsAtRest(t *testing.T) {
+	ctx := context.Background()
+	backing := NewMemoryStore()
+	store := NewEncryptingStore(backing, fakeKeyManager{authorized: true}, "card_number")
+
+	status := opstatus.StatusFailedPrecondition.WithDescription("card declined").
+		WithDetails("card_number", "4111111111111111", "attempt", 2)
+	if err := store.Put(ctx, "op-1", status, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := backing.Get(ctx, "op-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := stored.Status.Detail("card_number"); !strings.HasPrefix(value.(string), encryptedValuePrefix) {
+		t.Errorf("stored card_number = %v, want it encrypted", value)
+	}
+	if value, _ := stored.Status.Detail("attempt"); value != 2 {
+		t.Errorf("stored attempt = %v, want it in plain", value)
+	}
+
+	record, err := store.Get(ctx, "op-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := record.Status.Detail("card_number"); value != "4111111111111111" {
+		t.Errorf("card_number = %v, want it decrypted", value)
+	}
+	records, err := store.FindByCode(ctx, opstatus.CodeFailedPrecondition)
+	if err != nil || len(records) != 1 {
+		t.Fatalf("FindByCode() = %v, %v", records, err)
+	}
+	if value, _ := records[0].Status.Detail("card_number"); value != "4111111111111111" {
+		t.Errorf("card_number = %v, want it decrypted", value)
+	}
+
+	unauthorized := NewEncryptingStore(backing, fakeKeyManager{}, "card_number")
+	if _, err := unauthorized.Get(ctx, "op-1"); err == nil {
+		t.Error("an unauthorized consumer decrypted the details")
+	}
+}
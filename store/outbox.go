@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ikonglong/op-status"
+	operr "github.com/ikonglong/op-status/error"
+)
+
+// Execer executes statements. *sql.Tx implements it, so that outbox records are appended in the
+// transaction of the operation that failed, and *sql.DB too.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// OutboxRecord is a failed operation recorded in an Outbox, for a retry or compensation worker.
+type OutboxRecord struct {
+	ID          string
+	OperationID string
+	Status      *opstatus.Status
+	// PayloadRef locates the payload of the operation, e.g., the key of the request in a blob store,
+	// for the worker to replay or compensate it.
+	PayloadRef string
+	CreatedAt  time.Time
+}
+
+// Outbox is a table of failed operations, appended in the transactions of the operations, so that a
+// failure is recorded if and only if its transaction commits. Workers drive retries and
+// compensation from the statuses of the records: they read them with Pending, e.g., to relay them to
+// a topic, and remove them with Done once handled. It is safe for concurrent use.
+type Outbox struct {
+	db          *sql.DB
+	table       string
+	placeholder Placeholder
+	now         func() time.Time
+}
+
+// NewOutbox returns an Outbox keeping records in given table, which CreateTable can create. The
+// table name is part of the queries as is: it must not come from untrusted input. Its time is the
+// one of the opstatus package clock.
+func NewOutbox(db *sql.DB, table string, placeholder Placeholder) *Outbox {
+	return &Outbox{
+		db:          db,
+		table:       table,
+		placeholder: placeholder,
+		now:         opstatus.Now,
+	}
+}
+
+// CreateTable creates the table of this outbox if it doesn't exist.
+func (o *Outbox) CreateTable(ctx context.Context) error {
+	_, err := o.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+o.table+` (
+	id VARCHAR(64) NOT NULL PRIMARY KEY,
+	operation_id VARCHAR(255) NOT NULL,
+	code INTEGER NOT NULL,
+	status TEXT NOT NULL,
+	payload_ref TEXT NOT NULL,
+	created_at BIGINT NOT NULL
+)`)
+	if err != nil {
+		return databaseError("create table "+o.table, err)
+	}
+	return nil
+}
+
+// Append records the failure of given operation with given status through given transaction, and
+// returns the ID of the record.
+func (o *Outbox) Append(ctx context.Context, tx Execer, operationID string, status *opstatus.Status, payloadRef string) (string, error) {
+	if err := checkPut(operationID, status); err != nil {
+		return "", err
+	}
+	encoded, err := json.Marshal(status)
+	if err != nil {
+		return "", operr.NewWithStatusAndCause(*opstatus.StatusInternal.WithDescriptionf("encode status of operation %q", operationID), err)
+	}
+	id := opstatus.NewOperationID()
+	_, err = tx.ExecContext(ctx,
+		fmt.Sprintf(`INSERT INTO %s (id, operation_id, code, status, payload_ref, created_at) VALUES (%s, %s, %s, %s, %s, %s)`,
+			o.table, o.placeholder(1), o.placeholder(2), o.placeholder(3), o.placeholder(4), o.placeholder(5), o.placeholder(6)),
+		id, operationID, status.Code().Value(), string(encoded), payloadRef, o.now().UnixNano())
+	if err != nil {
+		return "", databaseError("append to outbox", err)
+	}
+	return id, nil
+}
+
+// Pending returns at most limit records of this outbox, oldest first.
+func (o *Outbox) Pending(ctx context.Context, limit int) ([]*OutboxRecord, error) {
+	rows, err := o.db.QueryContext(ctx,
+		`SELECT id, operation_id, status, payload_ref, created_at FROM `+o.table+` ORDER BY created_at LIMIT `+o.placeholder(1),
+		limit)
+	if err != nil {
+		return nil, databaseError("query outbox", err)
+	}
+	defer rows.Close()
+	var records []*OutboxRecord
+	for rows.Next() {
+		var (
+			record    OutboxRecord
+			encoded   string
+			createdAt int64
+		)
+		if err := rows.Scan(&record.ID, &record.OperationID, &encoded, &record.PayloadRef, &createdAt); err != nil {
+			return nil, databaseError("read outbox", err)
+		}
+		record.CreatedAt = time.Unix(0, createdAt)
+		record.Status = &opstatus.Status{}
+		if err := json.Unmarshal([]byte(encoded), record.Status); err != nil {
+			return nil, operr.NewWithStatusAndCause(*opstatus.StatusDataLoss.WithDescriptionf("decode status of outbox record %q", record.ID), err)
+		}
+		records = append(records, &record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, databaseError("read outbox", err)
+	}
+	return records, nil
+}
+
+// Done removes the record with given ID once handled. Removing a missing record is not an error, so
+// that workers can retry it.
+func (o *Outbox) Done(ctx context.Context, id string) error {
+	if _, err := o.db.ExecContext(ctx, `DELETE FROM `+o.table+` WHERE id = `+o.placeholder(1), id); err != nil {
+		return databaseError("remove outbox record", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ikonglong/op-status"
+)
+
+func TestOutboxAppendsInTheTransactionOfTheOperation(t *testing.T) {
+	ctx := context.Background()
+	clock := &fixedClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if err := opstatus.SetClock(clock); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { opstatus.SetClock(nil) })
+	fake := newFakeDB()
+	db := sql.OpenDB(fake)
+	t.Cleanup(func() { db.Close() })
+	outbox := NewOutbox(db, fakeOutboxTable, DollarPlaceholder)
+	if err := outbox.CreateTable(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, operationID := range []string{"op-1", "op-2"} {
+		clock.now = clock.now.Add(time.Duration(i) * time.Second)
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := outbox.Append(ctx, tx, operationID, opstatus.StatusUnavailable.WithDescription("payments are down"), "blob://requests/"+operationID); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pending, err := outbox.Pending(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].OperationID != "op-1" || pending[0].PayloadRef != "blob://requests/op-1" {
+		t.Fatalf("Pending(1) = %+v, want the record of op-1", pending)
+	}
+	if !pending[0].Status.IsTransient() || pending[0].Status.Description() != "payments are down" {
+		t.Errorf("status = %v %q, want the appended one", pending[0].Status.Code(), pending[0].Status.Description())
+	}
+
+	if err := outbox.Done(ctx, pending[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := outbox.Done(ctx, pending[0].ID); err != nil {
+		t.Errorf("Done() of a removed record = %v, want nil", err)
+	}
+	if pending, err = outbox.Pending(ctx, 10); err != nil || len(pending) != 1 || pending[0].OperationID != "op-2" {
+		t.Errorf("Pending(10) = %+v, %v, want the record of op-2", pending, err)
+	}
+}
@@ -0,0 +1,191 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ikonglong/op-status"
+	operr "github.com/ikonglong/op-status/error"
+)
+
+// Placeholder returns the bind parameter placeholder of the n-th argument of a query, starting at
+// 1, since SQL drivers disagree on it.
+type Placeholder func(n int) string
+
+// QuestionPlaceholder is the placeholder of, e.g., MySQL and SQLite: ?.
+func QuestionPlaceholder(int) string {
+	return "?"
+}
+
+// DollarPlaceholder is the placeholder of, e.g., PostgreSQL: $1, $2, ...
+func DollarPlaceholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// SQLStore is a StatusStore keeping records in a table of a SQL database. Statuses are stored in
+// their JSON encoding, so their causes aren't kept. Times are stored as Unix nanoseconds, a zero
+// expiration meaning none, so that the table is portable across databases. Expired records are no
+// longer returned but stay in the table until Purge deletes them. It is safe for concurrent use.
+type SQLStore struct {
+	db          *sql.DB
+	table       string
+	placeholder Placeholder
+	now         func() time.Time
+}
+
+// NewSQLStore returns a SQLStore keeping records in given table, which CreateTable can create.
+// The table name is part of the queries as is: it must not come from untrusted input. Its time is
+// the one of the opstatus package clock.
+func NewSQLStore(db *sql.DB, table string, placeholder Placeholder) *SQLStore {
+	return &SQLStore{
+		db:          db,
+		table:       table,
+		placeholder: placeholder,
+		now:         opstatus.Now,
+	}
+}
+
+// CreateTable creates the table of this store if it doesn't exist.
+func (s *SQLStore) CreateTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+s.table+` (
+	operation_id VARCHAR(255) NOT NULL PRIMARY KEY,
+	code INTEGER NOT NULL,
+	status TEXT NOT NULL,
+	stored_at BIGINT NOT NULL,
+	expires_at BIGINT NOT NULL
+)`)
+	if err != nil {
+		return databaseError("create table "+s.table, err)
+	}
+	return nil
+}
+
+// Put stores the JSON encoding of given status. Any previous record of the operation is replaced in
+// the same transaction.
+func (s *SQLStore) Put(ctx context.Context, operationID string, status *opstatus.Status, ttl time.Duration) error {
+	if err := checkPut(operationID, status); err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(status)
+	if err != nil {
+		return operr.NewWithStatusAndCause(*opstatus.StatusInternal.WithDescriptionf("encode status of operation %q", operationID), err)
+	}
+	now := s.now()
+	var expiresAt int64
+	if ttl > 0 {
+		expiresAt = now.Add(ttl).UnixNano()
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return databaseError("begin transaction", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM `+s.table+` WHERE operation_id = `+s.placeholder(1), operationID); err != nil {
+		return databaseError("delete previous status", err)
+	}
+	_, err = tx.ExecContext(ctx,
+		fmt.Sprintf(`INSERT INTO %s (operation_id, code, status, stored_at, expires_at) VALUES (%s, %s, %s, %s, %s)`,
+			s.table, s.placeholder(1), s.placeholder(2), s.placeholder(3), s.placeholder(4), s.placeholder(5)),
+		operationID, status.Code().Value(), string(encoded), now.UnixNano(), expiresAt)
+	if err != nil {
+		return databaseError("insert status", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return databaseError("commit transaction", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) Get(ctx context.Context, operationID string) (*Record, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT operation_id, status, stored_at, expires_at FROM `+s.table+` WHERE operation_id = `+s.placeholder(1),
+		operationID)
+	if err != nil {
+		return nil, databaseError("query status", err)
+	}
+	records, err := s.scan(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, notFound(operationID)
+	}
+	return records[0], nil
+}
+
+func (s *SQLStore) FindByCode(ctx context.Context, code opstatus.Code) ([]*Record, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT operation_id, status, stored_at, expires_at FROM `+s.table+` WHERE code = `+s.placeholder(1)+
+			` ORDER BY stored_at`,
+		code.Value())
+	if err != nil {
+		return nil, databaseError("query statuses", err)
+	}
+	return s.scan(rows)
+}
+
+// Purge deletes the expired records, e.g., from a periodic job, and returns how many were deleted.
+func (s *SQLStore) Purge(ctx context.Context) (int64, error) {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM `+s.table+` WHERE expires_at <> 0 AND expires_at <= `+s.placeholder(1),
+		s.now().UnixNano())
+	if err != nil {
+		return 0, databaseError("purge expired statuses", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, databaseError("purge expired statuses", err)
+	}
+	return deleted, nil
+}
+
+// scan reads the records of given rows, leaving the expired ones out.
+func (s *SQLStore) scan(rows *sql.Rows) ([]*Record, error) {
+	defer rows.Close()
+	now := s.now()
+	var records []*Record
+	for rows.Next() {
+		var (
+			record              Record
+			encoded             string
+			storedAt, expiresAt int64
+		)
+		if err := rows.Scan(&record.OperationID, &encoded, &storedAt, &expiresAt); err != nil {
+			return nil, databaseError("read status", err)
+		}
+		record.StoredAt = time.Unix(0, storedAt)
+		if expiresAt != 0 {
+			record.ExpiresAt = time.Unix(0, expiresAt)
+		}
+		if record.expired(now) {
+			continue
+		}
+		record.Status = &opstatus.Status{}
+		if err := json.Unmarshal([]byte(encoded), record.Status); err != nil {
+			return nil, operr.NewWithStatusAndCause(*opstatus.StatusDataLoss.WithDescriptionf("decode status of operation %q", record.OperationID), err)
+		}
+		records = append(records, &record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, databaseError("read statuses", err)
+	}
+	return records, nil
+}
+
+// databaseError returns the OpError of a failed database operation.
+func databaseError(action string, err error) error {
+	status := &opstatus.StatusUnavailable
+	switch {
+	case errors.Is(err, context.Canceled):
+		status = &opstatus.StatusCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		status = &opstatus.StatusDeadlineExceeded
+	}
+	return operr.NewWithStatusAndCause(*status.WithDescriptionf("status store: %s", action), err)
+}
@@ -0,0 +1,322 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ikonglong/op-status"
+	operr "github.com/ikonglong/op-status/error"
+)
+
+// fakeDB is an in-memory database understanding the queries of SQLStore only.
+type fakeDB struct {
+	mu      sync.Mutex
+	rows    map[string]fakeRow
+	outbox  []fakeOutboxRow
+	queries []string
+	// err, if set, fails every query.
+	err error
+}
+
+type fakeRow struct {
+	operationID         string
+	code                int64
+	status              string
+	storedAt, expiresAt int64
+}
+
+type fakeOutboxRow struct {
+	id, operationID string
+	code            int64
+	status          string
+	payloadRef      string
+	createdAt       int64
+}
+
+func newFakeDB() *fakeDB {
+	return &fakeDB{rows: map[string]fakeRow{}}
+}
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return fakeConn{db}, nil }
+func (db *fakeDB) Driver() driver.Driver                        { return fakeDriver{} }
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("fake driver: use sql.OpenDB")
+}
+
+type fakeConn struct{ db *fakeDB }
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("fake driver: prepared statements aren't supported")
+}
+func (fakeConn) Close() error              { return nil }
+func (fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+func (c fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	db := c.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.queries = append(db.queries, query)
+	if db.err != nil {
+		return nil, db.err
+	}
+	var affected int64
+	switch {
+	case strings.HasPrefix(query, "CREATE TABLE"):
+	case strings.Contains(query, fakeOutboxTable):
+		return db.execOutbox(query, args)
+	case strings.Contains(query, "WHERE operation_id ="):
+		if _, found := db.rows[args[0].Value.(string)]; found {
+			delete(db.rows, args[0].Value.(string))
+			affected++
+		}
+	case strings.Contains(query, "WHERE expires_at"):
+		for id, row := range db.rows {
+			if row.expiresAt != 0 && row.expiresAt <= args[0].Value.(int64) {
+				delete(db.rows, id)
+				affected++
+			}
+		}
+	case strings.HasPrefix(query, "INSERT INTO"):
+		row := fakeRow{
+			operationID: args[0].Value.(string),
+			code:        args[1].Value.(int64),
+			status:      args[2].Value.(string),
+			storedAt:    args[3].Value.(int64),
+			expiresAt:   args[4].Value.(int64),
+		}
+		db.rows[row.operationID] = row
+		affected++
+	default:
+		return nil, errors.New("fake driver: unexpected statement " + query)
+	}
+	return driver.RowsAffected(affected), nil
+}
+
+func (c fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	db := c.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.queries = append(db.queries, query)
+	if db.err != nil {
+		return nil, db.err
+	}
+	if strings.Contains(query, fakeOutboxTable) {
+		return db.queryOutbox(args)
+	}
+	var rows []fakeRow
+	for _, row := range db.rows {
+		switch {
+		case strings.Contains(query, "WHERE operation_id ="):
+			if row.operationID == args[0].Value.(string) {
+				rows = append(rows, row)
+			}
+		case strings.Contains(query, "WHERE code ="):
+			if row.code == args[0].Value.(int64) {
+				rows = append(rows, row)
+			}
+		default:
+			return nil, errors.New("fake driver: unexpected query " + query)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].storedAt < rows[j].storedAt })
+	result := &fakeRows{columns: []string{"operation_id", "status", "stored_at", "expires_at"}}
+	for _, row := range rows {
+		result.values = append(result.values, []driver.Value{row.operationID, row.status, row.storedAt, row.expiresAt})
+	}
+	return result, nil
+}
+
+// fakeOutboxTable is the table of the outboxes under test, whose statements the fake routes to
+// execOutbox and queryOutbox.
+const fakeOutboxTable = "failure_outbox"
+
+// execOutbox executes a statement on the outbox table. The caller must hold db.mu.
+func (db *fakeDB) execOutbox(query string, args []driver.NamedValue) (driver.Result, error) {
+	switch {
+	case strings.HasPrefix(query, "INSERT INTO"):
+		db.outbox = append(db.outbox, fakeOutboxRow{
+			id:          args[0].Value.(string),
+			operationID: args[1].Value.(string),
+			code:        args[2].Value.(int64),
+			status:      args[3].Value.(string),
+			payloadRef:  args[4].Value.(string),
+			createdAt:   args[5].Value.(int64),
+		})
+		return driver.RowsAffected(1), nil
+	case strings.Contains(query, "WHERE id ="):
+		for i, row := range db.outbox {
+			if row.id == args[0].Value.(string) {
+				db.outbox = append(db.outbox[:i], db.outbox[i+1:]...)
+				return driver.RowsAffected(1), nil
+			}
+		}
+		return driver.RowsAffected(0), nil
+	}
+	return nil, errors.New("fake driver: unexpected statement " + query)
+}
+
+// queryOutbox returns the oldest records of the outbox table. The caller must hold db.mu.
+func (db *fakeDB) queryOutbox(args []driver.NamedValue) (driver.Rows, error) {
+	rows := slices.Clone(db.outbox)
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].createdAt < rows[j].createdAt })
+	if limit := int(args[0].Value.(int64)); len(rows) > limit {
+		rows = rows[:limit]
+	}
+	result := &fakeRows{columns: []string{"id", "operation_id", "status", "payload_ref", "created_at"}}
+	for _, row := range rows {
+		result.values = append(result.values, []driver.Value{row.id, row.operationID, row.status, row.payloadRef, row.createdAt})
+	}
+	return result, nil
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func newTestSQLStore(t *testing.T, placeholder Placeholder) (*SQLStore, *fakeDB, *fixedClock) {
+	t.Helper()
+	clock := &fixedClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if err := opstatus.SetClock(clock); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { opstatus.SetClock(nil) })
+
+	fake := newFakeDB()
+	db := sql.OpenDB(fake)
+	t.Cleanup(func() { db.Close() })
+	store := NewSQLStore(db, "statuses", placeholder)
+	if err := store.CreateTable(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return store, fake, clock
+}
+
+func TestSQLStoreRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store, fake, clock := newTestSQLStore(t, DollarPlaceholder)
+
+	status := opstatus.NewBadRequest(opstatus.FieldViolation{Field: "/name", Description: "is required"})
+	if err := store.Put(ctx, "op-1", status, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	// Putting again replaces the record.
+	if err := store.Put(ctx, "op-1", status, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.rows) != 1 {
+		t.Errorf("rows = %d, want 1", len(fake.rows))
+	}
+	for _, query := range fake.queries {
+		if strings.Contains(query, "?") {
+			t.Errorf("query %q doesn't use the dollar placeholders", query)
+		}
+	}
+
+	record, err := store.Get(ctx, "op-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.OperationID != "op-1" || !record.StoredAt.Equal(clock.now) || !record.ExpiresAt.Equal(clock.now.Add(time.Hour)) {
+		t.Errorf("record = %+v", record)
+	}
+	if violations := record.Status.FieldViolations(); len(violations) != 1 || violations[0].Field != "/name" {
+		t.Errorf("FieldViolations() = %v, want the stored one", violations)
+	}
+
+	records, err := store.FindByCode(ctx, opstatus.CodeInvalidArgument)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].OperationID != "op-1" {
+		t.Errorf("FindByCode() = %v, want op-1", records)
+	}
+}
+
+func TestSQLStoreGetOfAMissingRecord(t *testing.T) {
+	store, _, _ := newTestSQLStore(t, QuestionPlaceholder)
+	_, err := store.Get(context.Background(), "op-1")
+	if status := operr.StatusFromErrChain(err); status == nil || status.Code() != opstatus.CodeNotFound {
+		t.Errorf("Get() = %v, want NotFound", err)
+	}
+}
+
+func TestSQLStorePurgesExpiredRecords(t *testing.T) {
+	ctx := context.Background()
+	store, fake, clock := newTestSQLStore(t, QuestionPlaceholder)
+	for id, ttl := range map[string]time.Duration{"short": time.Minute, "long": time.Hour, "forever": 0} {
+		if err := store.Put(ctx, id, opstatus.StatusUnavailable.WithDescription(id), ttl); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	if _, err := store.Get(ctx, "short"); err == nil {
+		t.Error("Get succeeded after the record expired")
+	}
+	deleted, err := store.Purge(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1 || len(fake.rows) != 2 {
+		t.Errorf("Purge() = %d leaving %d rows, want 1 leaving 2", deleted, len(fake.rows))
+	}
+	records, err := store.FindByCode(ctx, opstatus.CodeUnavailable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Errorf("FindByCode() = %d records, want 2", len(records))
+	}
+}
+
+func TestSQLStoreMapsDatabaseErrors(t *testing.T) {
+	tests := []struct {
+		err  error
+		want opstatus.Code
+	}{
+		{errors.New("connection refused"), opstatus.CodeUnavailable},
+		{context.Canceled, opstatus.CodeCancelled},
+		{context.DeadlineExceeded, opstatus.CodeDeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			store, fake, _ := newTestSQLStore(t, QuestionPlaceholder)
+			fake.err = tt.err
+			_, err := store.Get(context.Background(), "op-1")
+			if status := operr.StatusFromErrChain(err); status == nil || status.Code() != tt.want {
+				t.Errorf("Get() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
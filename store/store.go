@@ -0,0 +1,131 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ikonglong/op-status"
+	operr "github.com/ikonglong/op-status/error"
+)
+
+// Record is the terminal status of an operation kept by a StatusStore.
+type Record struct {
+	OperationID string
+	Status      *opstatus.Status
+	StoredAt    time.Time
+	// ExpiresAt is the time after which the record is no longer returned. The zero value means the
+	// record never expires.
+	ExpiresAt time.Time
+}
+
+// clone returns a copy of this record that doesn't share its status, so that callers can't change
+// the stored one.
+func (r *Record) clone() *Record {
+	cloned := *r
+	cloned.Status = r.Status.Clone()
+	return &cloned
+}
+
+func (r *Record) expired(now time.Time) bool {
+	return !r.ExpiresAt.IsZero() && !now.Before(r.ExpiresAt)
+}
+
+// StatusStore persists the terminal statuses of operations, e.g., async jobs, keyed by operation ID,
+// so that "what happened to my request" can be answered after the operation is gone.
+type StatusStore interface {
+	// Put stores given status as the terminal status of given operation, replacing any previous one.
+	// A non-positive ttl means the record never expires. The status must not be nil.
+	Put(ctx context.Context, operationID string, status *opstatus.Status, ttl time.Duration) error
+
+	// Get returns the record of given operation. If there is none, an OpError with status NotFound
+	// is returned.
+	Get(ctx context.Context, operationID string) (*Record, error)
+
+	// FindByCode returns the records whose status has given code, oldest first.
+	FindByCode(ctx context.Context, code opstatus.Code) ([]*Record, error)
+}
+
+// MemoryStore is a StatusStore keeping records in memory. It is safe for concurrent use.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]*Record
+	now     func() time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore. Its time is the one of the opstatus package clock.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: map[string]*Record{},
+		now:     opstatus.Now,
+	}
+}
+
+// checkPut tells why given status can't be stored for given operation, if it can't.
+func checkPut(operationID string, status *opstatus.Status) error {
+	if operationID == "" {
+		return operr.NewWithStatus(*opstatus.StatusInvalidArgument.WithDescription("operation ID is empty"))
+	}
+	if status == nil {
+		return operr.NewWithStatus(*opstatus.StatusInvalidArgument.WithDescriptionf("status of operation %q is nil", operationID))
+	}
+	return nil
+}
+
+// Put stores a copy of given status, so that later changes to the status don't affect the record.
+func (m *MemoryStore) Put(_ context.Context, operationID string, status *opstatus.Status, ttl time.Duration) error {
+	if err := checkPut(operationID, status); err != nil {
+		return err
+	}
+	now := m.now()
+	record := &Record{
+		OperationID: operationID,
+		Status:      status.Clone(),
+		StoredAt:    now,
+	}
+	if ttl > 0 {
+		record.ExpiresAt = now.Add(ttl)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records[operationID] = record
+	return nil
+}
+
+func (m *MemoryStore) Get(_ context.Context, operationID string) (*Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, found := m.records[operationID]
+	if found && record.expired(m.now()) {
+		delete(m.records, operationID)
+		found = false
+	}
+	if !found {
+		return nil, notFound(operationID)
+	}
+	return record.clone(), nil
+}
+
+func notFound(operationID string) error {
+	return operr.NewWithStatus(*opstatus.StatusNotFound.WithDescriptionf("no status recorded for operation %q", operationID))
+}
+
+func (m *MemoryStore) FindByCode(_ context.Context, code opstatus.Code) ([]*Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	var found []*Record
+	for id, record := range m.records {
+		if record.expired(now) {
+			delete(m.records, id)
+			continue
+		}
+		if record.Status.Code() == code {
+			found = append(found, record.clone())
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].StoredAt.Before(found[j].StoredAt) })
+	return found, nil
+}
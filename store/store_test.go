@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ikonglong/op-status"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fixedClock) After(d time.Duration) <-chan time.Time {
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func TestMemoryStorePutCopiesTheStatus(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	status := opstatus.StatusNotFound.WithDescription("job not found")
+	if err := store.Put(ctx, "op-1", status, 0); err != nil {
+		t.Fatal(err)
+	}
+	status.AddDetail("later", true)
+
+	record, err := store.Get(ctx, "op-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, found := record.Status.Details()["later"]; found {
+		t.Errorf("stored status was changed through the caller's pointer")
+	}
+}
+
+func TestMemoryStorePutRejectsNilStatus(t *testing.T) {
+	store := NewMemoryStore()
+	if err := store.Put(context.Background(), "op-1", nil, 0); err == nil {
+		t.Fatal("Put(nil) succeeded")
+	}
+	if _, err := store.FindByCode(context.Background(), opstatus.CodeNotFound); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemoryStoreUsesThePackageClock(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if err := opstatus.SetClock(clock); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { opstatus.SetClock(nil) })
+
+	ctx := context.Background()
+	store := NewMemoryStore()
+	if err := store.Put(ctx, "op-1", opstatus.StatusInternal.WithDescription("failed"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	record, err := store.Get(ctx, "op-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !record.StoredAt.Equal(clock.now) {
+		t.Errorf("StoredAt = %v, want %v", record.StoredAt, clock.now)
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	if _, err := store.Get(ctx, "op-1"); err == nil {
+		t.Errorf("Get succeeded after the record expired")
+	}
+}
@@ -0,0 +1,52 @@
+package opstatus
+
+// Transience tells whether a failure is expected to go away by itself, a simpler binary than
+// RetryAdvice for, e.g., circuit breakers and dead-letter routers.
+type Transience int
+
+const (
+	// TransienceUnspecified means the transience is derived from the code.
+	TransienceUnspecified Transience = iota
+	// Transient means the failure is expected to go away by itself, e.g., by retrying later.
+	Transient
+	// Permanent means the failure persists until something is changed.
+	Permanent
+)
+
+// transientCodes are the codes of the failures considered transient unless stated otherwise.
+var transientCodes = map[Code]bool{
+	CodeUnavailable:       true,
+	CodeDeadlineExceeded:  true,
+	CodeAborted:           true,
+	CodeResourceExhausted: true,
+}
+
+// WithTransience returns a derived instance of this Status whose transience is given one instead of
+// the one derived from its code, e.g., for a translator knowing better. TransienceUnspecified
+// restores the derivation from the code.
+func (s *Status) WithTransience(transience Transience) *Status {
+	derived := s.derive()
+	derived.transience = transience
+	return derived
+}
+
+// Transience returns the transience of this status: the one set by WithTransience if any, and
+// otherwise Transient for Unavailable, DeadlineExceeded, Aborted and ResourceExhausted, and
+// Permanent for the other failures. It is TransienceUnspecified for OK statuses.
+func (s *Status) Transience() Transience {
+	if s.transience != TransienceUnspecified {
+		return s.transience
+	}
+	if s.IsOK() {
+		return TransienceUnspecified
+	}
+	if transientCodes[s.code] {
+		return Transient
+	}
+	return Permanent
+}
+
+// IsTransient tells if this status is a transient failure.
+func (s *Status) IsTransient() bool {
+	return s.Transience() == Transient
+}
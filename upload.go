@@ -0,0 +1,71 @@
+package opstatus
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ikonglong/op-status/http"
+)
+
+const (
+	// DetailKeyHTTPStatus is the detail key of an HTTP status overriding the one mapped to the code
+	// of a status, set for the failures that have a more specific HTTP status, e.g., 413.
+	DetailKeyHTTPStatus = ReservedDetailKeyPrefix + "http_status"
+
+	// DetailKeyPayloadLimit is the detail key of the PayloadLimit attached by NewPayloadTooLarge.
+	DetailKeyPayloadLimit = ReservedDetailKeyPrefix + "payload_limit"
+
+	// DetailKeySupportedMediaTypes is the detail key of the media types listed by
+	// NewUnsupportedMediaType.
+	DetailKeySupportedMediaTypes = ReservedDetailKeyPrefix + "supported_media_types"
+)
+
+// PayloadLimit tells the size limit an upload exceeded.
+type PayloadLimit struct {
+	MaxBytes    int64 `json:"max_bytes"`
+	ActualBytes int64 `json:"actual_bytes,omitempty"`
+}
+
+// HTTPStatus returns the HTTP status of this status: the one overriding its code's, if any, or the
+// one its code is mapped to.
+func (s *Status) HTTPStatus() int {
+	if override, found := s.details[DetailKeyHTTPStatus].(http.Status); found {
+		return int(override)
+	}
+	return s.code.HTTPStatus()
+}
+
+// NewPayloadTooLarge returns a ResourceExhausted status for an upload larger than allowed, rendered
+// over HTTP as 413 Payload Too Large. The actual size may be 0 if unknown.
+func NewPayloadTooLarge(maxBytes, actualBytes int64) *Status {
+	status := StatusResourceExhausted.WithDescriptionf("the payload exceeds the limit of %d bytes", maxBytes)
+	status.setDetail(DetailKeyPayloadLimit, PayloadLimit{MaxBytes: maxBytes, ActualBytes: actualBytes})
+	status.setDetail(DetailKeyHTTPStatus, http.StatusPayloadTooLarge)
+	return status
+}
+
+// NewUnsupportedMediaType returns an InvalidArgument status for an upload of a media type that
+// isn't supported, rendered over HTTP as 415 Unsupported Media Type.
+func NewUnsupportedMediaType(mediaType string, supported ...string) *Status {
+	desc := fmt.Sprintf("media type %q is not supported", mediaType)
+	if len(supported) > 0 {
+		desc += "; supported: " + strings.Join(supported, ", ")
+	}
+	status := StatusInvalidArgument.WithDescription(desc)
+	if len(supported) > 0 {
+		status.setDetail(DetailKeySupportedMediaTypes, supported)
+	}
+	status.setDetail(DetailKeyHTTPStatus, http.StatusUnsupportedMediaType)
+	return status
+}
+
+// NewChecksumMismatch returns a DataLoss status for an upload whose content doesn't match the
+// checksum announced by the client.
+func NewChecksumMismatch(object, algorithm, expected, actual string) *Status {
+	return NewDataIntegrityLoss(DataIntegrityInfo{
+		Object:            object,
+		ChecksumAlgorithm: algorithm,
+		ExpectedChecksum:  expected,
+		ActualChecksum:    actual,
+	})
+}